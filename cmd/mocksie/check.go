@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/nickwallen/mocksie/internal"
+	"github.com/nickwallen/mocksie/internal/diff"
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/nickwallen/mocksie/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+// errOutOfDate indicates that a mock is out-of-date with its interface.
+var errOutOfDate = errors.New("out-of-date")
+
+// NewCheckCmd creates a command that checks whether a mock is up-to-date with its interface.
+func NewCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check that a generated mock is up-to-date with its interface",
+		Long: `
+Check regenerates a mock in memory and compares it with the output file. If they
+differ, the differences are printed as a unified diff and the check fails. The
+output file is never written to. If no interface is named, each of the mocks
+defined in the config file is checked.
+
+The version of mocksie that generated the mock is ignored so that a mock is only
+considered out-of-date when its content changes.
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Check the mocks defined in the config file, unless an interface is named
+			if !interfaceNamed(cmd) {
+				return checkConfigured(cmd)
+			}
+			if len(generateArgs.outFile) == 0 {
+				return errors.New("--out is required to check a mock")
+			}
+
+			// Find the interface definition
+			found, err := findInterface()
+			if err != nil {
+				return err
+			}
+			opts := generateOptions(cmd)
+			err = checkMock(cmd, generateArgs.outFile, found, opts)
+			if errors.Is(err, errOutOfDate) {
+				return fmt.Errorf("%s is out-of-date; regenerate it with: %s", generateArgs.outFile, opts.Header.Command)
+			}
+			return err
+		},
+	}
+	addGenerateFlags(cmd)
+
+	return cmd
+}
+
+// checkConfigured checks each of the mocks defined in the config file.
+func checkConfigured(cmd *cobra.Command) error {
+	if _, err := os.Stat(generateArgs.config); os.IsNotExist(err) {
+		return fmt.Errorf("no interface named; set --name or define the mocks in %s", generateArgs.config)
+	}
+	mocks, err := loadConfig(cmd, generateArgs.config)
+	if err != nil {
+		return err
+	}
+	var outOfDate []string
+	for _, mock := range mocks {
+		found, err := parser.FindInterfaceIn(mock.in, mock.name)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", mock.in, mock.name, err)
+		}
+		err = checkMock(cmd, mock.out, found, mock.opts)
+		if errors.Is(err, errOutOfDate) {
+			outOfDate = append(outOfDate, mock.out)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", mock.out, err)
+		}
+	}
+	if len(outOfDate) > 0 {
+		return fmt.Errorf("mocks are out-of-date: %s; regenerate them with: %s", strings.Join(outOfDate, ", "), commandLine(cmd))
+	}
+	return nil
+}
+
+// checkMock regenerates a mock in memory and prints the differences between it and
+// the output file. It returns errOutOfDate if there are any.
+func checkMock(cmd *cobra.Command, outFile string, found *mocksie.Interface, opts generator.Options) error {
+	// Read the existing mock, which is treated as empty if it does not exist
+	existing, err := ioutil.ReadFile(outFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Regenerate the mock as if by the same version of mocksie, and the same command. The
+	// paths in the command are relative to where it was run, like by go generate.
+	if header, ok := generator.ParseHeader(existing); ok {
+		opts.Header = header.Header
+	}
+	var regenerated bytes.Buffer
+	gen, err := generator.New(&regenerated, opts)
+	if err != nil {
+		return err
+	}
+	err = gen.GenerateMock(found)
+	if err != nil {
+		return err
+	}
+
+	// Report the differences, if any
+	differences := diff.Unified(outFile, outFile+" (regenerated)", existing, regenerated.Bytes())
+	if len(differences) == 0 {
+		return nil
+	}
+	fmt.Fprint(cmd.OutOrStdout(), differences)
+	return errOutOfDate
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// generateMock generates a mock of the greeter interface to a file in dir.
+func generateMock(t *testing.T, dir string) string {
+	outFile := filepath.Join(dir, "mockGreeter.go")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", outFile,
+	})
+	err := cmd.Execute()
+	require.NoError(t, err)
+	return outFile
+}
+
+// runCheck checks the mock of the greeter interface in outFile.
+func runCheck(outFile string) (string, error) {
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{
+		"check",
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", outFile,
+	})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func Test_CheckCmd_UpToDate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outFile := generateMock(t, dir)
+
+	out, err := runCheck(outFile)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func Test_CheckCmd_IgnoresVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outFile := generateMock(t, dir)
+
+	// Pretend the mock was generated by another version of mocksie
+	mock, err := ioutil.ReadFile(outFile)
+	require.NoError(t, err)
+	mock = bytes.Replace(mock, []byte("mocksie (devel)"), []byte("mocksie v1.2.3"), 1)
+	err = ioutil.WriteFile(outFile, mock, 0600)
+	require.NoError(t, err)
+
+	out, err := runCheck(outFile)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func Test_CheckCmd_IgnoresCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outFile := generateMock(t, dir)
+
+	// Pretend the mock was generated from another directory, like by go generate
+	mock, err := ioutil.ReadFile(outFile)
+	require.NoError(t, err)
+	mock = bytes.ReplaceAll(mock, []byte("../../internal/testdata/greeter.go"), []byte("greeter.go"))
+	mock = bytes.Replace(mock, []byte("--out "+outFile), []byte("--out mockGreeter.go"), 1)
+	err = ioutil.WriteFile(outFile, mock, 0600)
+	require.NoError(t, err)
+
+	out, err := runCheck(outFile)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func Test_CheckCmd_OutOfDate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outFile := generateMock(t, dir)
+
+	// Change the mock as if the interface had changed
+	mock, err := ioutil.ReadFile(outFile)
+	require.NoError(t, err)
+	stale := bytes.Replace(mock, []byte("DoSayGoodbye"), []byte("DoSayFarewell"), -1)
+	err = ioutil.WriteFile(outFile, stale, 0600)
+	require.NoError(t, err)
+
+	out, err := runCheck(outFile)
+	require.EqualError(t, err, outFile+" is out-of-date; regenerate it with: "+
+		"mocksie --in ../../internal/testdata/greeter.go --name greeter --out "+outFile)
+	require.Contains(t, out, "--- "+outFile+"\n+++ "+outFile+" (regenerated)\n")
+	require.Contains(t, out, "-\tDoSayFarewell func(in io.Reader, out io.Writer) error\n")
+	require.Contains(t, out, "+\tDoSayGoodbye func(in io.Reader, out io.Writer) error\n")
+
+	// The mock must not be changed
+	actual, err := ioutil.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, string(stale), string(actual))
+}
+
+func Test_CheckCmd_DoesNotExist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outFile := filepath.Join(dir, "mockGreeter.go")
+
+	out, err := runCheck(outFile)
+	require.Error(t, err)
+	require.Contains(t, out, "@@ -0,0 +1,")
+	require.NoFileExists(t, outFile)
+}
@@ -1,22 +1,49 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"runtime/debug"
+	"strings"
 
+	"github.com/nickwallen/mocksie/internal"
 	"github.com/nickwallen/mocksie/internal/generator"
 	"github.com/nickwallen/mocksie/internal/parser"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var generateArgs = struct {
-	inFile  string // Input file containing the interface definition.
-	outFile string // Output file to write the generated mock to.
-	name    string // Name of the interface to generate a mock for.
+	inFile    string // Input file containing the interface definition.
+	outFile   string // Output file to write the generated mock to.
+	name      string // Name of the interface to generate a mock for.
+	returns   bool   // Generate helpers that queue the results returned by each method.
+	calls     bool   // Record the calls made to each method.
+	delegate  bool   // Forward calls without a Do function to a delegate implementation, and record them.
+	expect    bool   // Generate helpers that define the results of each method for matching arguments.
+	order     bool   // Number the calls made to the mock so that their order can be verified.
+	context   bool   // Return the error of a context once it is done.
+	faults    bool   // Generate helpers that inject latency and errors.
+	wait      bool   // Generate helpers that wait for the calls made to each method.
+	gates     bool   // Generate helpers that block calls until they are released.
+	reset     bool   // Generate helpers that reset, and take a snapshot of, the state of the mock.
+	callLog   bool   // Generate helpers that render the calls made to the mock.
+	golden    bool   // Record calls to, and replay calls from, a golden file.
+	noAssert  bool   // Skip the compile-time assertion that the mock implements the interface.
+	mockName  string // Template that defines the name of the mock.
+	templates string // Directory or file containing templates that override the built-in templates.
+	export    bool   // Export the mock so that it can be used from other packages.
+	config    string // Config file defining the mocks to generate when no interface is named.
+	fromIR    string // File containing the intermediate representation of the interface.
+	emitIR    bool   // Write the intermediate representation of the interface rather than its mock.
+	force     bool   // Overwrite output files that were not generated by mocksie.
 }{}
 
-// NewGenerateCmd a command that generates mock implementations of an interface.
-func NewGenerateCmd() *cobra.Command {
+// NewRootCmd creates the mocksie command. Like the generate command, it generates
+// mocks when invoked with only flags, so that existing go:generate directives work.
+func NewRootCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "mocksie",
 		Short: "Mocksie will generate mocks for your Golang interfaces ",
@@ -29,45 +56,260 @@ additional mocking or test packages. This allows you to define mock behavior
 directly in the test case, does not require learning a new mocking framework,
 prevents you from having to maintain boilerplate code, and 
 `,
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			out := cmd.OutOrStdout()
-			log.SetOutput(out)
-
-			// Find the interface definition
-			p, err := parser.New(generateArgs.inFile)
-			if err != nil {
-				return err
-			}
-			found, err := p.FindInterface(generateArgs.name)
-			if err != nil {
-				return err
-			}
-
-			// Open the output file or use stdout if not output file defined
-			if len(generateArgs.outFile) > 0 {
-				outFile, err := os.Create(generateArgs.outFile)
-				if err != nil {
-					return err
-				}
-				defer outFile.Close() // TODO handle the error
-				out = outFile
-			}
-
-			// Generate the mock
-			gen, err := generator.New(out)
-			if err != nil {
-				return err
-			}
-			return gen.GenerateMock(found)
-		},
+		RunE: runGenerate,
+	}
+	addGenerateFlags(cmd)
+	addEmitIRFlag(cmd)
+	addForceFlag(cmd)
+
+	cmd.AddCommand(NewGenerateCmd())
+	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewCheckCmd())
+	cmd.AddCommand(NewScanCmd())
+	cmd.AddCommand(NewInitCmd())
+	cmd.AddCommand(NewVersionCmd())
+	return cmd
+}
+
+// NewGenerateCmd a command that generates mock implementations of an interface.
+func NewGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a mock for an interface, or the mocks defined in the config file",
+		Long: `
+Generate generates a mock for the interface given by --name. If no interface is
+named, each of the mocks defined in the config file is generated.
+`,
+		Args: cobra.NoArgs,
+		RunE: runGenerate,
+	}
+	addGenerateFlags(cmd)
+	addEmitIRFlag(cmd)
+	addForceFlag(cmd)
+
+	return cmd
+}
+
+// runGenerate generates a mock for the named interface, or the mocks defined in
+// the config file if no interface is named.
+func runGenerate(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	log.SetOutput(out)
+
+	// Generate the mocks defined in the config file, unless an interface is named
+	if !interfaceNamed(cmd) {
+		return generateConfigured(cmd)
+	}
+
+	// Find the interface definition
+	found, err := findInterface()
+	if err != nil {
+		return err
+	}
+	if generateArgs.emitIR {
+		err = writeIR(out, generateArgs.outFile, found, generateArgs.force)
+	} else {
+		err = writeMock(out, generateArgs.outFile, found, generateOptions(cmd), generateArgs.force)
 	}
+	if err != nil && len(generateArgs.outFile) > 0 {
+		return fmt.Errorf("%s: %w", generateArgs.outFile, err)
+	}
+	return err
+}
 
-	// Define the accepted flags
-	cmd.Flags().StringVarP(&generateArgs.inFile, "in", "i", "", "The input file containing the interface definition.")
+// addGenerateFlags defines the flags that identify the interface and control how
+// its mock is generated.
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&generateArgs.inFile, "in", "i", "", "The input file, or package directory, containing the interface definition.")
 	cmd.Flags().StringVarP(&generateArgs.outFile, "out", "o", "", "The output file to write the generated mocks to.")
 	cmd.Flags().StringVarP(&generateArgs.name, "name", "n", "", "The name of the interface to generate a mock for.")
-	err := cmd.MarkFlagRequired("name")
-	cobra.CheckErr(err)
+	cmd.Flags().StringVar(&generateArgs.config, "config", DefaultConfigFile, "The config file defining the mocks to generate when no interface is named.")
+	cmd.Flags().StringVar(&generateArgs.fromIR, "from-ir", "", "A JSON file containing the intermediate representation of the interface, rather than its definition.")
+	addOptionFlags(cmd)
+}
 
-	return cmd
+// addForceFlag defines the flag that overwrites output files that were not generated
+// by mocksie.
+func addForceFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&generateArgs.force, "force", false, "Overwrite output files that were not generated by mocksie.")
+}
+
+// addEmitIRFlag defines the flag that writes the intermediate representation of
+// the interface, rather than its mock.
+func addEmitIRFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&generateArgs.emitIR, "emit-ir", false, "Write the intermediate representation of the interface as JSON, rather than its mock.")
+}
+
+// addOptionFlags defines the flags that control how a mock is generated.
+func addOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&generateArgs.returns, "returns", false, "Generate helpers that queue the results returned by each method.")
+	cmd.Flags().BoolVar(&generateArgs.calls, "calls", false, "Record the calls made to each method.")
+	cmd.Flags().BoolVar(&generateArgs.delegate, "delegate", false, "Forward calls to methods without a Do function to a delegate implementation, and record them.")
+	cmd.Flags().BoolVar(&generateArgs.expect, "expect", false, "Generate helpers that define the results of each method when called with matching arguments.")
+	cmd.Flags().BoolVar(&generateArgs.order, "order", false, "Number the calls made to the mock so that their order can be verified.")
+	cmd.Flags().BoolVar(&generateArgs.context, "context", false, "Return the error of a method's context once it is done.")
+	cmd.Flags().BoolVar(&generateArgs.faults, "faults", false, "Generate helpers that inject latency and errors into each method that returns an error.")
+	cmd.Flags().BoolVar(&generateArgs.wait, "wait", false, "Generate helpers that wait for the calls made to each method.")
+	cmd.Flags().BoolVar(&generateArgs.gates, "gates", false, "Generate helpers that block the calls made to each method until they are released.")
+	cmd.Flags().BoolVar(&generateArgs.reset, "reset", false, "Generate helpers that reset, and take a snapshot of, the state of the mock.")
+	cmd.Flags().BoolVar(&generateArgs.callLog, "call-log", false, "Generate helpers that render the calls made to the mock and log them when a test fails.")
+	cmd.Flags().BoolVar(&generateArgs.golden, "golden", false, "Record calls to, and replay calls from, a golden file.")
+	cmd.Flags().BoolVar(&generateArgs.noAssert, "no-assert", false, "Skip the compile-time assertion that the mock implements the interface.")
+	cmd.Flags().StringVar(&generateArgs.mockName, "mock-name", generator.DefaultMockName, "The template that defines the name of the mock.")
+	cmd.Flags().StringVar(&generateArgs.templates, "templates", "", "A directory or file containing templates that override the built-in templates.")
+	cmd.Flags().BoolVar(&generateArgs.export, "export", false, "Export the mock so that it can be used from other packages.")
+}
+
+// findInterface finds the definition of the interface to generate a mock for.
+func findInterface() (*mocksie.Interface, error) {
+	if len(generateArgs.fromIR) == 0 {
+		return parser.FindInterfaceIn(generateArgs.inFile, generateArgs.name)
+	}
+
+	// Read the interface from its intermediate representation
+	file, err := os.Open(generateArgs.fromIR)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	found, err := mocksie.DecodeIR(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", generateArgs.fromIR, err)
+	}
+	if len(generateArgs.name) > 0 && generateArgs.name != found.Name {
+		return nil, fmt.Errorf("%s: interface %q not found; it defines %q", generateArgs.fromIR, generateArgs.name, found.Name)
+	}
+	return found, nil
+}
+
+// interfaceNamed returns true if an interface is named, or read from its intermediate
+// representation, rather than defined in the config file.
+func interfaceNamed(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("name") || len(generateArgs.fromIR) > 0
+}
+
+// generateConfigured generates each of the mocks defined in the config file.
+func generateConfigured(cmd *cobra.Command) error {
+	if _, err := os.Stat(generateArgs.config); os.IsNotExist(err) {
+		return fmt.Errorf("no interface named; set --name or define the mocks in %s", generateArgs.config)
+	}
+	mocks, err := loadConfig(cmd, generateArgs.config)
+	if err != nil {
+		return err
+	}
+	for _, mock := range mocks {
+		found, err := parser.FindInterfaceIn(mock.in, mock.name)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", mock.in, mock.name, err)
+		}
+		err = writeMock(nil, mock.out, found, mock.opts, generateArgs.force)
+		if err != nil {
+			return fmt.Errorf("%s: %w", mock.out, err)
+		}
+	}
+	return nil
+}
+
+// writeIR writes the IR of an interface to a file, or to out if no file is given.
+// An existing file is only overwritten if it is an IR document, unless forced.
+func writeIR(out io.Writer, outFile string, found *mocksie.Interface, force bool) error {
+	if len(outFile) > 0 && !force {
+		err := ensureIR(outFile)
+		if err != nil {
+			return err
+		}
+	}
+	return writeOutput(out, outFile, func(w io.Writer) error {
+		return mocksie.EncodeIR(w, found)
+	})
+}
+
+// writeMock generates a mock and writes it to a file, or to out if no file is given.
+// An existing file is only overwritten if it was generated by mocksie, unless forced.
+func writeMock(out io.Writer, outFile string, found *mocksie.Interface, opts generator.Options, force bool) error {
+	if len(outFile) > 0 && !force {
+		err := ensureGenerated(outFile)
+		if err != nil {
+			return err
+		}
+	}
+	return writeOutput(out, outFile, func(w io.Writer) error {
+		gen, err := generator.New(w, opts)
+		if err != nil {
+			return err
+		}
+		return gen.GenerateMock(found)
+	})
+}
+
+// generateOptions returns the options used to generate a mock.
+func generateOptions(cmd *cobra.Command) generator.Options {
+	source := generateArgs.inFile
+	if len(generateArgs.fromIR) > 0 {
+		source = generateArgs.fromIR
+	}
+	return generator.Options{
+		Returns:   generateArgs.returns,
+		Calls:     generateArgs.calls,
+		Delegate:  generateArgs.delegate,
+		Expect:    generateArgs.expect,
+		Order:     generateArgs.order,
+		Context:   generateArgs.context,
+		Faults:    generateArgs.faults,
+		Wait:      generateArgs.wait,
+		Gates:     generateArgs.gates,
+		Reset:     generateArgs.reset,
+		CallLog:   generateArgs.callLog,
+		Golden:    generateArgs.golden,
+		NoAssert:  generateArgs.noAssert,
+		MockName:  generateArgs.mockName,
+		Templates: generateArgs.templates,
+		Export:    generateArgs.export,
+		Header: generator.Header{
+			Version: version(),
+			Source:  source,
+			Command: commandLine(cmd),
+		},
+	}
+}
+
+// version returns the version of mocksie recorded when it was built.
+func version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || len(info.Main.Version) == 0 {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+// commandLine returns the command line that regenerates the mock; the args follow
+// the name of the root command. Only the flags that were set, other than --force,
+// are included in lexicographical order.
+func commandLine(cmd *cobra.Command, args ...string) string {
+	line := []string{cmd.Root().Name()}
+	for _, arg := range args {
+		line = append(line, quoteArg(arg))
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		// Forcing the output file to be overwritten does not change the mock
+		if flag.Name == "force" {
+			return
+		}
+		if flag.Value.Type() == "bool" && flag.Value.String() == "true" {
+			line = append(line, "--"+flag.Name)
+			return
+		}
+		line = append(line, "--"+flag.Name, quoteArg(flag.Value.String()))
+	})
+	return strings.Join(line, " ")
+}
+
+// quoteArg quotes an argument so that it can be passed to a shell as is.
+func quoteArg(arg string) string {
+	if len(arg) > 0 && strings.IndexFunc(arg, func(r rune) bool {
+		return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%", r)
+	}) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
 }
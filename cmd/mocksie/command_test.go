@@ -2,7 +2,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,7 +20,7 @@ func Test_GenerateCmd_OK(t *testing.T) {
 	require.NoError(t, err)
 
 	// Run the command
-	cmd := NewGenerateCmd()
+	cmd := NewRootCmd()
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{
 		"--name", "greeter",
@@ -31,7 +35,7 @@ func Test_GenerateCmd_InterfaceNotFound(t *testing.T) {
 	var out bytes.Buffer
 
 	// Run the command
-	cmd := NewGenerateCmd()
+	cmd := NewRootCmd()
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{
 		"--name", "doesNotExist", // An interface by this name does not exist
@@ -49,7 +53,7 @@ func Test_GenerateCmd_ChooseOneInterface(t *testing.T) {
 	require.NoError(t, err)
 
 	// The input file has multiple interfaces defined
-	cmd := NewGenerateCmd()
+	cmd := NewRootCmd()
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{
 		"--name", "helloGreeter",
@@ -74,7 +78,7 @@ func Test_GenerateCmd_OutFile(t *testing.T) {
 	require.NoError(t, err)
 
 	// Generate a mock
-	cmd := NewGenerateCmd()
+	cmd := NewRootCmd()
 	cmd.SetArgs([]string{
 		"--name", "greeter",
 		"--in", "../../internal/testdata/greeter.go",
@@ -83,8 +87,199 @@ func Test_GenerateCmd_OutFile(t *testing.T) {
 	err = cmd.Execute()
 	require.NoError(t, err)
 
+	// The command recorded in the header includes the output file
+	expectedMock = bytes.Replace(expectedMock,
+		[]byte("--name greeter\n"),
+		[]byte("--name greeter --out "+outFile.Name()+"\n"), 1)
+
 	// Validate the generated mock
 	generatedMock, err := ioutil.ReadFile(outFile.Name())
 	require.NoError(t, err)
 	require.Equal(t, string(expectedMock), string(generatedMock))
 }
+
+func Test_commandLine(t *testing.T) {
+	cmd := NewRootCmd()
+	err := cmd.ParseFlags([]string{
+		"--name", "greeter",
+		"--in", "greeter.go",
+		"--returns",
+		"--mock-name", "{{ .Name }}Mock",
+		"--templates", "it's",
+	})
+	require.NoError(t, err)
+	require.Equal(t, `mocksie --in greeter.go --mock-name '{{ .Name }}Mock' --name greeter --returns --templates 'it'\''s'`, commandLine(cmd))
+}
+
+func Test_GenerateCmd_Subcommand(t *testing.T) {
+	var out bytes.Buffer
+
+	// Read the expected mock output
+	expectedMock, err := ioutil.ReadFile("../../internal/testdata/mockGreeter.go")
+	require.NoError(t, err)
+
+	// The generate command records the same command line as the flags alone
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{
+		"generate",
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+	})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, string(expectedMock), out.String())
+}
+
+func Test_ListCmd_OK(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "../../internal/testdata/greeters.go"})
+	err := cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, `INTERFACE       METHOD                                         POSITION                                  MOCK
+helloGreeter                                                   ../../internal/testdata/greeters.go:11:6  ../../internal/testdata/mockHelloGreeter.go
+                SayHello(in io.Reader, out io.Writer) error    ../../internal/testdata/greeters.go:12:2
+goodbyeGreeter                                                 ../../internal/testdata/greeters.go:27:6  ../../internal/testdata/mockGoodbyeGreeter.go
+                SayGoodbye(in io.Reader, out io.Writer) error  ../../internal/testdata/greeters.go:28:2
+`, out.String())
+}
+
+func Test_ListCmd_JSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "list")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"store.go": `package store
+
+type store[K comparable, V any] interface {
+	Get(key K) (value V, err error)
+	Close() //mocksie:ignore
+}
+`,
+	})
+
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "--format", "json", dir})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	var listed []listedInterface
+	err = json.Unmarshal(out.Bytes(), &listed)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "store.go")
+	require.Equal(t, []listedInterface{
+		{
+			Name:       "store",
+			Package:    "store",
+			TypeParams: []string{"K comparable", "V any"},
+			Position:   listedPosition{Filename: path, Line: 3, Column: 6},
+			Methods: []listedMethod{
+				{
+					Name:      "Get",
+					Signature: "Get(key K) (value V, err error)",
+					Position:  listedPosition{Filename: path, Line: 4, Column: 2},
+				},
+				{
+					Name:      "Close",
+					Signature: "Close()",
+					Position:  listedPosition{Filename: path, Line: 5, Column: 2},
+					Ignored:   true,
+				},
+			},
+		},
+	}, listed)
+}
+
+func Test_ListCmd_UnknownFormat(t *testing.T) {
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"list", "--format", "yaml"})
+	err := cmd.Execute()
+	require.EqualError(t, err, `unknown format "yaml"; expected table or json`)
+}
+
+func Test_InitCmd_OK(t *testing.T) {
+	dir, err := ioutil.TempDir("", "init")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config := filepath.Join(dir, DefaultConfigFile)
+
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetArgs([]string{"init", "--config", config})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, initConfig, readMock(t, config))
+
+	// The config file must not be overwritten
+	cmd = NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"init", "--config", config})
+	err = cmd.Execute()
+	require.EqualError(t, err, config+" already exists")
+}
+
+func Test_VersionCmd_OK(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"version"})
+	err := cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, "mocksie (devel)\n", out.String())
+}
+
+func Test_GenerateCmd_IR(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	irFile := filepath.Join(dir, "greeter.json")
+
+	// Write the intermediate representation of the interface
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--emit-ir",
+		"--out", irFile,
+	})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, irFile), `"version": 1,`)
+
+	// Generate the mock from the intermediate representation
+	var out bytes.Buffer
+	cmd = NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"generate", "--from-ir", irFile})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	// The mock is the same, other than its source
+	expectedMock := readMock(t, "../../internal/testdata/mockGreeter.go")
+	expectedMock = strings.Replace(expectedMock,
+		"// Source:    ../../internal/testdata/greeter.go\n// Command:   mocksie --in ../../internal/testdata/greeter.go --name greeter\n",
+		"// Source:    "+irFile+"\n// Command:   mocksie --from-ir "+irFile+"\n", 1)
+	require.Equal(t, expectedMock, out.String())
+}
+
+func Test_GenerateCmd_IR_WrongName(t *testing.T) {
+	irFile, err := ioutil.TempFile("", "greeter.json")
+	require.NoError(t, err)
+	defer os.Remove(irFile.Name())
+	_, err = irFile.WriteString(`{"version": 1, "interface": {"name": "greeter", "package": "main"}}`)
+	require.NoError(t, err)
+
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"--from-ir", irFile.Name(), "--name", "helloGreeter"})
+	err = cmd.Execute()
+	require.EqualError(t, err, irFile.Name()+`: interface "helloGreeter" not found; it defines "greeter"`)
+}
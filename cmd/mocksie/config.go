@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// DefaultConfigFile is the config file that defines the mocks generated when no
+// interface is named.
+const DefaultConfigFile = ".mocksie.yaml"
+
+// optionKeys are the keys of the options that can be defined for all mocks, or
+// for each mock, in a config file. Each is named after its flag.
+var optionKeys = []string{
+	"returns", "calls", "delegate", "expect", "order", "context", "faults", "wait", "gates", "reset", "call-log",
+	"golden", "no-assert", "mock-name", "templates", "export",
+}
+
+// configuredMock is a mock defined in a config file.
+type configuredMock struct {
+	in   string // File or package directory containing the interface definition.
+	out  string // Output file to write the generated mock to.
+	name string // Name of the interface to generate a mock for.
+	opts generator.Options
+}
+
+// loadConfig loads the mocks defined in a config file. The options at the top of
+// the file apply to every mock, unless overridden by the mock. Environment variables,
+// like MOCKSIE_RETURNS, and flags override both. Paths in the file are relative to it.
+func loadConfig(cmd *cobra.Command, path string) ([]configuredMock, error) {
+	config, err := newConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+	config.SetConfigFile(path)
+	err = config.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+	var mocks []map[string]interface{}
+	err = config.UnmarshalKey("mocks", &mocks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(mocks) == 0 {
+		return nil, fmt.Errorf("%s: no mocks defined", path)
+	}
+
+	dir := filepath.Dir(path)
+	configured := make([]configuredMock, 0, len(mocks))
+	for i, mock := range mocks {
+		// Each mock defaults to the options defined for all mocks
+		v, err := newConfig(cmd)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range optionKeys {
+			v.SetDefault(key, config.Get(key))
+		}
+		err = v.MergeConfigMap(mock)
+		if err != nil {
+			return nil, fmt.Errorf("%s: mock %d: %w", path, i+1, err)
+		}
+		// Unlike the options, these cannot be overridden for every mock
+		in, out, name := mockString(mock, "in"), mockString(mock, "out"), mockString(mock, "name")
+		if len(in) == 0 || len(out) == 0 || len(name) == 0 {
+			return nil, fmt.Errorf("%s: mock %d must define in, out and name", path, i+1)
+		}
+
+		// Only templates defined in the config file are relative to it
+		templates := v.GetString("templates")
+		if !overridden(cmd, "templates") {
+			templates = relativeTo(dir, templates)
+		}
+
+		in = relativeTo(dir, in)
+		configured = append(configured, configuredMock{
+			in:   in,
+			out:  relativeTo(dir, out),
+			name: name,
+			opts: generator.Options{
+				Returns:   v.GetBool("returns"),
+				Calls:     v.GetBool("calls"),
+				Delegate:  v.GetBool("delegate"),
+				Expect:    v.GetBool("expect"),
+				Order:     v.GetBool("order"),
+				Context:   v.GetBool("context"),
+				Faults:    v.GetBool("faults"),
+				Wait:      v.GetBool("wait"),
+				Gates:     v.GetBool("gates"),
+				Reset:     v.GetBool("reset"),
+				CallLog:   v.GetBool("call-log"),
+				Golden:    v.GetBool("golden"),
+				NoAssert:  v.GetBool("no-assert"),
+				MockName:  v.GetString("mock-name"),
+				Templates: templates,
+				Export:    v.GetBool("export"),
+				Header: generator.Header{
+					Version: version(),
+					Source:  in,
+					Command: commandLine(cmd),
+				},
+			},
+		})
+	}
+	return configured, nil
+}
+
+// newConfig returns a config in which the options can be overridden by environment
+// variables and flags.
+func newConfig(cmd *cobra.Command) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvPrefix("mocksie")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+	for _, key := range optionKeys {
+		err := v.BindPFlag(key, cmd.Flags().Lookup(key))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// overridden reports whether an option is overridden by a flag or environment variable,
+// rather than defined in the config file.
+func overridden(cmd *cobra.Command, key string) bool {
+	if cmd.Flags().Changed(key) {
+		return true
+	}
+	env := "MOCKSIE_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+	return len(os.Getenv(env)) > 0
+}
+
+// mockString returns the value of a key defined by a mock in the config file, or an
+// empty string if it is not defined or not a string.
+func mockString(mock map[string]interface{}, key string) string {
+	value, _ := mock[key].(string)
+	return value
+}
+
+// relativeTo returns a path relative to a directory, unless it is absolute or empty.
+func relativeTo(dir, path string) string {
+	if len(path) == 0 || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
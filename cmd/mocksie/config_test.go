@@ -0,0 +1,234 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// config defines two mocks; the first uses the options defined for all mocks and
+// the second overrides them.
+const config = `
+returns: true
+mocks:
+  - in: testdata/greeter.go
+    out: mockGreeter.go
+    name: greeter
+  - in: testdata
+    out: helloGreeterMock.go
+    name: helloGreeter
+    returns: false
+    mock-name: "{{ .Name }}Mock"
+`
+
+// writeConfig writes the config, and the interfaces that it refers to, to a directory.
+func writeConfig(t *testing.T, dir, config string) string {
+	err := os.Mkdir(filepath.Join(dir, "testdata"), 0700)
+	require.NoError(t, err)
+	for _, name := range []string{"greeter.go", "greeters.go"} {
+		code, err := ioutil.ReadFile(filepath.Join("../../internal/testdata", name))
+		require.NoError(t, err)
+		err = ioutil.WriteFile(filepath.Join(dir, "testdata", name), code, 0600)
+		require.NoError(t, err)
+	}
+	path := filepath.Join(dir, DefaultConfigFile)
+	err = ioutil.WriteFile(path, []byte(config), 0600)
+	require.NoError(t, err)
+	return path
+}
+
+// readMock reads a generated mock.
+func readMock(t *testing.T, path string) string {
+	mock, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(mock)
+}
+
+func Test_GenerateCmd_Config(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, config)
+
+	// Generate the mocks without naming an interface
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	mock := readMock(t, filepath.Join(dir, "mockGreeter.go"))
+	require.Contains(t, mock, "// Source:    "+filepath.Join(dir, "testdata/greeter.go")+"\n")
+	require.Contains(t, mock, "// Command:   mocksie --config "+path+"\n")
+	require.Contains(t, mock, "func (m *mockGreeter) SayHelloReturns(")
+	require.NotContains(t, mock, "func (m *mockGreeter) SayHelloCalls(")
+
+	mock = readMock(t, filepath.Join(dir, "helloGreeterMock.go"))
+	require.Contains(t, mock, "type helloGreeterMock struct {")
+	require.NotContains(t, mock, "func (m *helloGreeterMock) SayHelloReturns(")
+}
+
+func Test_GenerateCmd_Config_Overrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, config)
+
+	// Environment variables and flags override the config file
+	err = os.Setenv("MOCKSIE_CALLS", "true")
+	require.NoError(t, err)
+	defer os.Unsetenv("MOCKSIE_CALLS")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"--config", path, "--mock-name", "fake{{ .Name | upperFirst }}"})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	mock := readMock(t, filepath.Join(dir, "mockGreeter.go"))
+	require.Contains(t, mock, "func (m *fakeGreeter) SayHelloReturns(")
+	require.Contains(t, mock, "func (m *fakeGreeter) SayHelloCalls(")
+
+	mock = readMock(t, filepath.Join(dir, "helloGreeterMock.go"))
+	require.Contains(t, mock, "func (m *fakeHelloGreeter) SayHelloCalls(")
+	require.NotContains(t, mock, "func (m *fakeHelloGreeter) SayHelloReturns(")
+}
+
+func Test_GenerateCmd_Config_Environment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, config)
+
+	// Environment variables do not override where each mock is read from and written to
+	for _, key := range []string{"MOCKSIE_IN", "MOCKSIE_OUT", "MOCKSIE_NAME"} {
+		err = os.Setenv(key, "doesNotExist")
+		require.NoError(t, err)
+		defer os.Unsetenv(key)
+	}
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	require.Contains(t, readMock(t, filepath.Join(dir, "mockGreeter.go")), "type mockGreeter struct {")
+	require.Contains(t, readMock(t, filepath.Join(dir, "helloGreeterMock.go")), "type helloGreeterMock struct {")
+	require.NoFileExists(t, filepath.Join(dir, "doesNotExist"))
+}
+
+func Test_GenerateCmd_Config_Templates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, "templates: config.tmpl\n"+config)
+	writeTemplates := func(dir, name string) string {
+		file := filepath.Join(dir, name)
+		err := ioutil.WriteFile(file, []byte(`{{ define "ignored" }}// `+name+`{{ end }}`), 0600)
+		require.NoError(t, err)
+		return file
+	}
+	writeTemplates(dir, "config.tmpl")
+
+	// Templates defined in the config file are relative to it
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, filepath.Join(dir, "mockGreeter.go")), "// config.tmpl")
+
+	// But those defined by flags or environment variables are relative to the working directory
+	wd, err := ioutil.TempDir(".", "templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(wd)
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"--config", path, "--templates", writeTemplates(wd, "flag.tmpl")})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, filepath.Join(dir, "mockGreeter.go")), "// flag.tmpl")
+
+	err = os.Setenv("MOCKSIE_TEMPLATES", writeTemplates(wd, "env.tmpl"))
+	require.NoError(t, err)
+	defer os.Unsetenv("MOCKSIE_TEMPLATES")
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, filepath.Join(dir, "mockGreeter.go")), "// env.tmpl")
+}
+
+func Test_GenerateCmd_Config_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "no-mocks",
+			config: "returns: true\n",
+			err:    "no mocks defined",
+		},
+		{
+			name:   "missing-out",
+			config: "mocks:\n  - in: testdata/greeter.go\n    name: greeter\n",
+			err:    "mock 1 must define in, out and name",
+		},
+		{
+			name:   "interface-not-found",
+			config: "mocks:\n  - in: testdata/greeter.go\n    out: mock.go\n    name: doesNotExist\n",
+			err:    "doesNotExist: interface not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "config")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			path := writeConfig(t, dir, test.config)
+
+			cmd := NewRootCmd()
+			cmd.SetOut(ioutil.Discard)
+			cmd.SetErr(ioutil.Discard)
+			cmd.SetArgs([]string{"--config", path})
+			err = cmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func Test_GenerateCmd_NoConfig(t *testing.T) {
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"--config", "doesNotExist.yaml"})
+	err := cmd.Execute()
+	require.EqualError(t, err, "no interface named; set --name or define the mocks in doesNotExist.yaml")
+}
+
+func Test_CheckCmd_Config(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeConfig(t, dir, config)
+
+	// Generate the mocks, which are then up-to-date
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"check", "--config", path})
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	// Remove one of the mocks, which is then out-of-date
+	err = os.Remove(filepath.Join(dir, "helloGreeterMock.go"))
+	require.NoError(t, err)
+	cmd = NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"check", "--config", path})
+	err = cmd.Execute()
+	require.EqualError(t, err, "mocks are out-of-date: "+filepath.Join(dir, "helloGreeterMock.go")+
+		"; regenerate them with: mocksie --config "+path)
+}
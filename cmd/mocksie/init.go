@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// initConfig is the config file created by the init command.
+const initConfig = `# The options defined here apply to every mock, unless overridden by the mock.
+# Each is named after its flag; like returns, calls or mock-name. Environment
+# variables, like MOCKSIE_RETURNS, and flags override them.
+returns: false
+
+# Each mock is generated for the interface with the given name, which is defined
+# in a file or package directory. Paths are relative to this file. Generate the
+# mocks by running mocksie without naming an interface.
+mocks:
+#  - in: greeter.go
+#    out: mock_greeter_test.go
+#    name: greeter
+`
+
+// NewInitCmd creates a command that creates a config file.
+func NewInitCmd() *cobra.Command {
+	var config string
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a config file defining the mocks to generate",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if _, err := os.Stat(config); err == nil {
+				return fmt.Errorf("%s already exists", config)
+			}
+			err := ioutil.WriteFile(config, []byte(initConfig), 0644)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "created %s\n", config)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&config, "config", DefaultConfigFile, "The config file to create.")
+
+	return cmd
+}
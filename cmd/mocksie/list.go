@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nickwallen/mocksie/internal"
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/nickwallen/mocksie/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+// listedInterface is an interface listed by the list command.
+type listedInterface struct {
+	Name       string         `json:"name"`
+	Package    string         `json:"package"`
+	TypeParams []string       `json:"typeParams,omitempty"` // Like K comparable.
+	Position   listedPosition `json:"position"`
+	Methods    []listedMethod `json:"methods"`
+	Mock       string         `json:"mock,omitempty"` // The file containing the generated mock, if it exists.
+}
+
+// listedMethod is a method of an interface listed by the list command.
+type listedMethod struct {
+	Name      string         `json:"name"`
+	Signature string         `json:"signature"`
+	Position  listedPosition `json:"position"`
+	Ignored   bool           `json:"ignored,omitempty"`
+}
+
+// listedPosition is the position of a declaration listed by the list command.
+type listedPosition struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// String returns the position as filename:line:column.
+func (p listedPosition) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// NewListCmd creates a command that lists the interfaces that mocks can be generated for.
+func NewListCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "list [files or packages]",
+		Short: "List the interfaces that mocks can be generated for",
+		Long: `
+List finds the interfaces declared in the given files, or package directories,
+which default to the current directory. Each interface is listed along with the
+signatures of its methods, their positions and whether a mock generated by mocksie
+already exists in the same directory.
+
+The interfaces are listed in a table, or in JSON with --format json.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unknown format %q; expected table or json", format)
+			}
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			listed := make([]listedInterface, 0)
+			mocks := make(map[string]map[string]string)
+			for _, path := range args {
+				found, err := parser.FindInterfacesIn(path)
+				if err != nil {
+					return err
+				}
+				for _, iface := range found {
+					// Find the mocks in the directory of the interface once
+					dir := filepath.Dir(iface.Position.Filename)
+					if _, ok := mocks[dir]; !ok {
+						mocks[dir], err = findMocks(dir)
+						if err != nil {
+							return err
+						}
+					}
+					listed = append(listed, listInterface(iface, mocks[dir][iface.Name]))
+				}
+			}
+			if format == "json" {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(listed)
+			}
+			return writeTable(cmd.OutOrStdout(), listed)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "table", "The format in which the interfaces are listed; table or json.")
+
+	return cmd
+}
+
+// listInterface returns an interface, along with the file containing its mock, as
+// it is listed.
+func listInterface(iface *mocksie.Interface, mock string) listedInterface {
+	methods := make([]listedMethod, 0, len(iface.Methods)+len(iface.Ignored))
+	for _, method := range iface.Methods {
+		methods = append(methods, listMethod(method, false))
+	}
+	for _, method := range iface.Ignored {
+		methods = append(methods, listMethod(method, true))
+	}
+	typeParams := make([]string, 0, len(iface.TypeParams))
+	for _, typeParam := range iface.TypeParams {
+		typeParams = append(typeParams, typeParam.Name+" "+typeParam.Constraint)
+	}
+	return listedInterface{
+		Name:       iface.Name,
+		Package:    string(iface.Package),
+		TypeParams: typeParams,
+		Position:   listedPosition(iface.Position),
+		Methods:    methods,
+		Mock:       mock,
+	}
+}
+
+// listMethod returns a method as it is listed.
+func listMethod(method mocksie.Method, ignored bool) listedMethod {
+	return listedMethod{
+		Name:      method.Name,
+		Signature: signature(method),
+		Position:  listedPosition(method.Position),
+		Ignored:   ignored,
+	}
+}
+
+// signature returns the signature of a method; like SayHello(name string) (string, error).
+func signature(method mocksie.Method) string {
+	params := make([]string, 0, len(method.Params))
+	for _, param := range method.Params {
+		params = append(params, strings.TrimSpace(param.Name+" "+param.Type))
+	}
+	results := make([]string, 0, len(method.Results))
+	for _, result := range method.Results {
+		results = append(results, strings.TrimSpace(result.Name+" "+result.Type))
+	}
+	sig := method.Name + "(" + strings.Join(params, ", ") + ")"
+	switch {
+	case len(results) == 0:
+		return sig
+	case len(results) == 1 && len(method.Results[0].Name) == 0:
+		return sig + " " + results[0]
+	default:
+		return sig + " (" + strings.Join(results, ", ") + ")"
+	}
+}
+
+// findMocks returns the files containing the mocks generated by mocksie in a
+// directory, keyed by the name of the interface that each implements.
+func findMocks(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	mocks := make(map[string]string)
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if header, ok := generator.ParseHeader(src); ok {
+			mocks[header.Interface] = file
+		}
+	}
+	return mocks, nil
+}
+
+// writeTable writes the interfaces as a table. Paths are relative to the working
+// directory where possible.
+func writeTable(out io.Writer, listed []listedInterface) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			return rel
+		}
+		return path
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INTERFACE\tMETHOD\tPOSITION\tMOCK")
+	for _, iface := range listed {
+		position := iface.Position
+		position.Filename = relative(position.Filename)
+		mock := "-"
+		if len(iface.Mock) > 0 {
+			mock = relative(iface.Mock)
+		}
+		fmt.Fprintf(w, "%s\t\t%s\t%s\n", iface.Name, position, mock)
+		for _, method := range iface.Methods {
+			position := method.Position
+			position.Filename = relative(position.Filename)
+			sig := method.Signature
+			if method.Ignored {
+				sig += " (ignored)"
+			}
+			fmt.Fprintf(w, "\t%s\t%s\n", sig, position)
+		}
+	}
+	return w.Flush()
+}
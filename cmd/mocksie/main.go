@@ -5,7 +5,7 @@ import (
 )
 
 func main() {
-	cmd := NewGenerateCmd()
+	cmd := NewRootCmd()
 	err := cmd.Execute()
 	cobra.CheckErr(err)
 }
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/nickwallen/mocksie/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+// NewScanCmd creates a command that generates mocks for the interfaces annotated
+// with a //mocksie:generate comment.
+func NewScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan [packages]",
+		Short: "Generate mocks for the interfaces annotated with //mocksie:generate",
+		Long: `
+Scan finds the interfaces annotated with a //mocksie:generate comment in the given
+packages, which default to the current directory, and generates a mock for each.
+Like the go tool, a package ending in /... includes its subdirectories.
+
+The annotation can be followed by options; the output file (out=mock_greeter.go),
+which defaults to mock_<interface>_test.go, the name of the mock (name=fakeGreeter),
+and any of the flags (returns or calls=false). The flags define the options for every annotated interface. Methods
+annotated with //mocksie:ignore are not mocked.
+
+    //mocksie:generate out=mock_greeter_test.go returns
+    type greeter interface {
+        SayHello(name string) (string, error)
+        //mocksie:ignore
+        SayGoodbye(name string) (string, error)
+    }
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			dirs, err := packageDirs(args)
+			if err != nil {
+				return err
+			}
+			for _, dir := range dirs {
+				files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+				if err != nil {
+					return err
+				}
+				for _, file := range files {
+					err = generateAnnotated(cmd, file, args)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+	addOptionFlags(cmd)
+	addForceFlag(cmd)
+
+	return cmd
+}
+
+// generateAnnotated generates a mock for each of the annotated interfaces in a file.
+func generateAnnotated(cmd *cobra.Command, file string, patterns []string) error {
+	p, err := parser.New(file)
+	if err != nil {
+		return err
+	}
+	annotated, err := p.FindAnnotatedInterfaces()
+	if err != nil {
+		return err
+	}
+	for _, iface := range annotated {
+		opts := generateOptions(cmd)
+		opts.Header.Source = file
+		opts.Header.Command = commandLine(cmd, append([]string{"scan"}, patterns...)...)
+		outFile, err := annotatedOptions(&opts, iface.Options, filepath.Dir(file))
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", file, iface.Name, err)
+		}
+
+		// The output file is a test file named after the interface by default
+		if len(outFile) == 0 {
+			outFile = filepath.Join(filepath.Dir(file), "mock_"+strings.ToLower(iface.Name)+"_test.go")
+		}
+
+		err = writeMock(nil, outFile, iface.Interface, opts, generateArgs.force)
+		if err != nil {
+			return fmt.Errorf("%s: %w", outFile, err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), outFile)
+	}
+	return nil
+}
+
+// annotatedOptions applies the options of a //mocksie:generate annotation and
+// returns the output file, if defined. Paths are relative to the directory of the
+// annotated interface.
+func annotatedOptions(opts *generator.Options, options map[string]string, dir string) (string, error) {
+	flags := map[string]*bool{
+		"returns":   &opts.Returns,
+		"calls":     &opts.Calls,
+		"delegate":  &opts.Delegate,
+		"expect":    &opts.Expect,
+		"order":     &opts.Order,
+		"context":   &opts.Context,
+		"faults":    &opts.Faults,
+		"wait":      &opts.Wait,
+		"gates":     &opts.Gates,
+		"reset":     &opts.Reset,
+		"call-log":  &opts.CallLog,
+		"golden":    &opts.Golden,
+		"no-assert": &opts.NoAssert,
+		"export":    &opts.Export,
+	}
+
+	// The options are applied in a consistent order so that any error is too
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var outFile string
+	for _, key := range keys {
+		value := options[key]
+		switch key {
+		case "out":
+			outFile = relativeTo(dir, value)
+		case "name":
+			opts.MockName = value
+		case "templates":
+			opts.Templates = relativeTo(dir, value)
+		default:
+			flag, ok := flags[key]
+			if !ok {
+				return "", fmt.Errorf("unknown option %q", key)
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid value %q for option %q", value, key)
+			}
+			*flag = enabled
+		}
+	}
+	return outFile, nil
+}
+
+// packageDirs returns the directories of the packages matched by patterns. Like the
+// go tool, a pattern ending in /... matches a directory and its subdirectories,
+// except for testdata, vendor and those starting with a dot or underscore.
+func packageDirs(patterns []string) ([]string, error) {
+	dirs := make([]string, 0)
+	for _, pattern := range patterns {
+		if pattern != "..." && !strings.HasSuffix(pattern, "/...") {
+			dirs = append(dirs, pattern)
+			continue
+		}
+		root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if len(root) == 0 {
+			root = "."
+		}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return err
+			}
+			name := info.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes files, keyed by their path, to a directory.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for path, code := range files {
+		path = filepath.Join(dir, path)
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		require.NoError(t, err)
+		err = ioutil.WriteFile(path, []byte(code), 0600)
+		require.NoError(t, err)
+	}
+}
+
+func Test_ScanCmd_OK(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"greeter.go": `package main
+
+//mocksie:generate
+type greeter interface {
+	SayHello(name string) (string, error)
+	//mocksie:ignore
+	SayGoodbye(name string) (string, error)
+}
+`,
+		"store/store.go": `package store
+
+//mocksie:generate out=mock_store_test.go name=fakeStore calls=false
+type store interface {
+	Get(key string) (string, error)
+}
+
+type notAnnotated interface {
+	Put(key string, value string) error
+}
+`,
+		"testdata/skipped.go": `package testdata
+
+//mocksie:generate
+type skipped interface {
+	Get(key string) (string, error)
+}
+`,
+	})
+
+	// Scan the directory and its subdirectories
+	var out bytes.Buffer
+	cmd := NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"scan", "--calls", dir + "/..."})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "mock_greeter_test.go")+"\n"+filepath.Join(dir, "store/mock_store_test.go")+"\n", out.String())
+
+	mock := readMock(t, filepath.Join(dir, "mock_greeter_test.go"))
+	require.Contains(t, mock, "// Source:    "+filepath.Join(dir, "greeter.go")+"\n")
+	require.Contains(t, mock, "// Command:   mocksie scan "+dir+"/... --calls\n")
+	require.Contains(t, mock, "func (m *mockGreeter) SayHelloCalls(")
+	require.Contains(t, mock, "// SayGoodbye is ignored by the mock and panics if called.\n")
+
+	mock = readMock(t, filepath.Join(dir, "store/mock_store_test.go"))
+	require.Contains(t, mock, "type fakeStore struct {")
+	require.NotContains(t, mock, "func (m *fakeStore) GetCalls(")
+	require.NoFileExists(t, filepath.Join(dir, "testdata/mock_skipped_test.go"))
+}
+
+func Test_ScanCmd_InvalidOption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"greeter.go": `package main
+
+//mocksie:generate returns=maybe
+type greeter interface {
+	SayHello(name string) (string, error)
+}
+`,
+	})
+
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"scan", dir})
+	err = cmd.Execute()
+	require.EqualError(t, err, filepath.Join(dir, "greeter.go")+`: greeter: invalid value "maybe" for option "returns"`)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nickwallen/mocksie/internal/generator"
+)
+
+// writeOutput calls write with the output file, or with out if no file is given.
+// The output file is only replaced once write succeeds.
+func writeOutput(out io.Writer, outFile string, write func(io.Writer) error) error {
+	if len(outFile) == 0 {
+		return write(out)
+	}
+	var buf bytes.Buffer
+	err := write(&buf)
+	if err != nil {
+		return err
+	}
+	return writeFile(outFile, buf.Bytes())
+}
+
+// writeFile atomically replaces the contents of a file. The contents are written
+// to a temporary file in the same directory, which is then renamed, so that the
+// file is never left partially written. A file that is unchanged is not written
+// so that its modification time is kept.
+func writeFile(path string, data []byte) (err error) {
+	mode := os.FileMode(0644)
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		existing, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(existing, data) {
+			return nil
+		}
+		mode = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	// Write to a temporary file, which is removed unless it is renamed
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ensureGenerated returns an error if a file exists that was not generated by
+// mocksie, so that source code is not mistakenly overwritten. Empty files, and
+// mocks generated before mocks had a header, are assumed to be safe to overwrite.
+func ensureGenerated(path string) error {
+	return ensureOverwritable(path, func(existing []byte) bool {
+		_, ok := generator.ParseHeader(existing)
+		return ok || generator.IsLegacyMock(existing)
+	})
+}
+
+// ensureIR returns an error if a file exists that is not an IR document, so
+// that source code is not mistakenly overwritten by --emit-ir.
+func ensureIR(path string) error {
+	return ensureOverwritable(path, isIR)
+}
+
+// ensureOverwritable returns an error if a file exists that is neither empty
+// nor recognized by generated.
+func ensureOverwritable(path string, generated func([]byte) bool) error {
+	existing, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(existing)) == 0 || generated(existing) {
+		return nil
+	}
+	return errors.New("not generated by mocksie; use --force to overwrite it")
+}
+
+// isIR reports whether src looks like an IR document, of any version.
+func isIR(src []byte) bool {
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(src, &doc) != nil {
+		return false
+	}
+	_, hasVersion := doc["version"]
+	_, hasInterface := doc["interface"]
+	return hasVersion && hasInterface
+}
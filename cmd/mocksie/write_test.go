@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeOutput_New(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mock.go")
+
+	err = writeOutput(nil, path, func(w io.Writer) error {
+		_, err := w.Write([]byte("package main\n"))
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, "package main\n", readMock(t, path))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	requireOnlyFile(t, dir, "mock.go")
+}
+
+func Test_writeOutput_Failure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mock.go")
+	err = ioutil.WriteFile(path, []byte("package main\n"), 0600)
+	require.NoError(t, err)
+
+	// The existing file is kept when the output cannot be written in full
+	err = writeOutput(nil, path, func(w io.Writer) error {
+		_, err := w.Write([]byte("package"))
+		require.NoError(t, err)
+		return errors.New("failed")
+	})
+	require.EqualError(t, err, "failed")
+	require.Equal(t, "package main\n", readMock(t, path))
+	requireOnlyFile(t, dir, "mock.go")
+}
+
+func Test_writeFile_Changed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mock.go")
+	err = ioutil.WriteFile(path, []byte("package main\n"), 0600)
+	require.NoError(t, err)
+
+	// The file is replaced, keeping its mode
+	err = writeFile(path, []byte("package mocks\n"))
+	require.NoError(t, err)
+	require.Equal(t, "package mocks\n", readMock(t, path))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	requireOnlyFile(t, dir, "mock.go")
+}
+
+func Test_writeFile_Unchanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mock.go")
+	err = ioutil.WriteFile(path, []byte("package main\n"), 0600)
+	require.NoError(t, err)
+	modified := time.Now().Add(-time.Hour).Truncate(time.Second)
+	err = os.Chtimes(path, modified, modified)
+	require.NoError(t, err)
+
+	// The file is not written, so its modification time is kept
+	err = writeFile(path, []byte("package main\n"))
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.True(t, modified.Equal(info.ModTime()))
+}
+
+// requireOnlyFile requires that a directory only contains the named file; no
+// temporary files are left behind.
+func requireOnlyFile(t *testing.T, dir, name string) {
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, name, entries[0].Name())
+}
+
+func Test_GenerateCmd_RefuseToOverwrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "greeter.go")
+	handWritten := "package main\n\nfunc main() {}\n"
+	err = ioutil.WriteFile(path, []byte(handWritten), 0600)
+	require.NoError(t, err)
+
+	// A file that was not generated by mocksie is not overwritten
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", path,
+	})
+	err = cmd.Execute()
+	require.EqualError(t, err, path+": not generated by mocksie; use --force to overwrite it")
+	require.Equal(t, handWritten, readMock(t, path))
+
+	// Unless forced, which is not recorded in the header
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", path,
+		"--force",
+	})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	mock := readMock(t, path)
+	require.Contains(t, mock, "// Command:   mocksie --in ../../internal/testdata/greeter.go --name greeter --out "+path+"\n")
+
+	// A file generated by mocksie is overwritten
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", path,
+		"--returns",
+	})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, path), "func (m *mockGreeter) SayHelloReturns(")
+}
+
+func Test_GenerateCmd_OverwriteLegacyMock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mockGreeter.go")
+	legacy, err := ioutil.ReadFile("../../internal/testdata/legacy/mockGreeter.go")
+	require.NoError(t, err)
+	err = ioutil.WriteFile(path, legacy, 0600)
+	require.NoError(t, err)
+
+	// A mock generated before mocks had a header is overwritten
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", path,
+	})
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, path), "// Code generated by mocksie (devel). DO NOT EDIT.\n")
+}
+
+func Test_GenerateCmd_EmitIR_RefuseToOverwrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "greeter.go")
+	handWritten := "package main\n\nfunc main() {}\n"
+	err = ioutil.WriteFile(path, []byte(handWritten), 0600)
+	require.NoError(t, err)
+	args := []string{
+		"--name", "greeter",
+		"--in", "../../internal/testdata/greeter.go",
+		"--out", path,
+		"--emit-ir",
+	}
+
+	// A file that is not an IR document is not overwritten
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	require.EqualError(t, err, path+": not generated by mocksie; use --force to overwrite it")
+	require.Equal(t, handWritten, readMock(t, path))
+
+	// Unless forced
+	cmd = NewRootCmd()
+	cmd.SetArgs(append(args, "--force"))
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, path), `"version": 1`)
+
+	// An IR document is overwritten
+	cmd = NewRootCmd()
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, readMock(t, path), `"name": "greeter"`)
+}
+
+func Test_ScanCmd_RefuseToOverwrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "write")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"greeter.go": `package main
+
+//mocksie:generate out=main.go
+type greeter interface {
+	SayHello(name string) (string, error)
+}
+`,
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+
+	cmd := NewRootCmd()
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	cmd.SetArgs([]string{"scan", dir})
+	err = cmd.Execute()
+	path := filepath.Join(dir, "main.go")
+	require.EqualError(t, err, path+": not generated by mocksie; use --force to overwrite it")
+	require.Equal(t, "package main\n\nfunc main() {}\n", readMock(t, path))
+}
@@ -0,0 +1,18 @@
+package mocksie
+
+import "fmt"
+
+// UnsupportedError is the error returned when an interface uses a construct that
+// cannot be mocked.
+type UnsupportedError struct {
+	// Construct is the construct that is not supported; like embedded interface io.Reader.
+	Construct string
+
+	// Position is the position of the construct within its source file.
+	Position Position
+}
+
+// Error returns the position and description of the unsupported construct.
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: unsupported %s", e.Position.Filename, e.Position.Line, e.Position.Column, e.Construct)
+}
@@ -0,0 +1,41 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CallLog(t *testing.T) {
+	ctx := context.Background()
+	m := newMockStoreT(t)
+	m.Delegate = &mapStore{}
+	m.Sequence = new(uint64)
+	err := m.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	_, err = m.Get(ctx, "missing")
+	require.Equal(t, errNotFound, err)
+
+	require.Equal(t, `1: Put(ctx: context.Background, key: "key", value: "value") -> <nil>
+2: Get(ctx: context.Background, key: "missing") -> ("", not found)
+`, m.String())
+}
+
+func Test_CallLog_SharedSequence(t *testing.T) {
+	ctx := context.Background()
+	sequence := new(uint64)
+	s := newMockStoreFrom(&mapStore{})
+	s.Sequence = sequence
+	n := &mockNotifier{DoNotify: func(string) {}, Sequence: sequence}
+	err := s.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	n.Notify("key")
+	_, err = s.Get(ctx, "key")
+	require.NoError(t, err)
+
+	// The calls are numbered by the sequence shared with the notifier
+	require.Equal(t, `1: Put(ctx: context.Background, key: "key", value: "value") -> <nil>
+3: Get(ctx: context.Background, key: "key") -> ("value", <nil>)
+`, s.CallLog())
+}
@@ -0,0 +1,18 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Context_BlocksUntilDone(t *testing.T) {
+	m := (&mockStore{}).GetBlocksUntilDone()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := m.Get(ctx, "key")
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Len(t, m.GetCalls(), 1)
+}
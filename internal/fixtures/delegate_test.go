@@ -0,0 +1,26 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Delegate_RecordsCalls(t *testing.T) {
+	ctx := context.Background()
+	m := newMockStoreFrom(&mapStore{})
+	err := m.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	_, err = m.Get(ctx, "missing")
+	require.Equal(t, errNotFound, err)
+
+	require.Equal(t, []mockStorePutCall{{
+		Seq:      m.PutCalls()[0].Seq,
+		Args:     mockStorePutArgs{Ctx: ctx, Key: "key", Value: "value"},
+		Results:  mockStorePutResults{},
+		sequence: &mockStoreSequence,
+	}}, m.PutCalls())
+	require.Len(t, m.GetCalls(), 1)
+	require.Equal(t, errNotFound, m.GetCalls()[0].Results.R1)
+}
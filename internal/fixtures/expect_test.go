@@ -0,0 +1,42 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Expect_Fallback(t *testing.T) {
+	ctx := context.Background()
+	expect := func(m *mockStore) *mockStore {
+		m.OnGet(ctx, "expected").Return("value", nil)
+		m.OnGetMatch(func(_ context.Context, key string) bool { return len(key) == 1 }).Return("short", nil)
+		return m
+	}
+
+	// A call that matches an expectation returns its results
+	m := expect(&mockStore{})
+	value, err := m.Get(ctx, "expected")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+	value, err = m.Get(ctx, "k")
+	require.NoError(t, err)
+	require.Equal(t, "short", value)
+
+	// Otherwise, it panics unless there is a Do function or delegate to fall back to
+	require.PanicsWithValue(t, `mockStore: no expectation of Get matches the arguments {Ctx:context.Background Key:unexpected}; the closest expects {Ctx:context.Background Key:expected}`, func() {
+		_, _ = m.Get(ctx, "unexpected")
+	})
+
+	m = expect(&mockStore{DoGet: func(context.Context, string) (string, error) { return "do", nil }})
+	value, err = m.Get(ctx, "unexpected")
+	require.NoError(t, err)
+	require.Equal(t, "do", value)
+
+	m = expect(newMockStoreFrom(&mapStore{values: map[string]string{"unexpected": "delegated"}}))
+	value, err = m.Get(ctx, "unexpected")
+	require.NoError(t, err)
+	require.Equal(t, "delegated", value)
+	require.Len(t, m.GetCalls(), 1)
+}
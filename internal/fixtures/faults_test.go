@@ -0,0 +1,47 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Faults_Seeded(t *testing.T) {
+	ctx := context.Background()
+	injected := func(seed int64) []bool {
+		m := &mockStore{DoPut: func(context.Context, string, string) error { return nil }}
+		m.PutFaults(mockStoreFaults{Probability: 0.5, Seed: seed})
+		var injected []bool
+		for i := 0; i < 32; i++ {
+			err := m.Put(ctx, "key", "value")
+			injected = append(injected, errors.Is(err, mockStoreErrInjected))
+		}
+		return injected
+	}
+
+	// The same seed injects the same errors
+	require.Equal(t, injected(1), injected(1))
+	require.NotEqual(t, injected(1), injected(2))
+	require.Contains(t, injected(1), true)
+	require.Contains(t, injected(1), false)
+}
+
+func Test_Faults_EveryNth(t *testing.T) {
+	ctx := context.Background()
+	errFault := errors.New("fault")
+	m := &mockStore{DoGet: func(context.Context, string) (string, error) { return "value", nil }}
+	m.GetFaults(mockStoreFaults{EveryNth: 2, Err: errFault})
+
+	for i := 1; i <= 4; i++ {
+		value, err := m.Get(ctx, "key")
+		if i%2 == 0 {
+			require.Equal(t, errFault, err)
+			require.Empty(t, value)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, "value", value)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package fixtures defines the interfaces whose mocks are generated, with every
+// option, so that their runtime behavior can be tested.
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+//go:generate go run ../../cmd/mocksie --in fixtures.go --name store --out mock_store_test.go --returns --expect --delegate --golden --order --context --faults --wait --gates --reset --call-log
+//go:generate go run ../../cmd/mocksie --in fixtures.go --name notifier --out mock_notifier_test.go --order --reset
+
+// errNotFound is returned by a mapStore when a key is not found.
+var errNotFound = errors.New("not found")
+
+// store is a key-value store.
+type store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Put(ctx context.Context, key string, value string) error
+}
+
+// notifier is notified of the keys that are changed.
+type notifier interface {
+	Notify(key string)
+}
+
+// mapStore is a store that keeps its values in a map.
+type mapStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// Get returns the value of a key.
+func (s *mapStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if !ok {
+		return "", errNotFound
+	}
+	return value, nil
+}
+
+// Put sets the value of a key.
+func (s *mapStore) Put(_ context.Context, key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]string)
+	}
+	s.values[key] = value
+	return nil
+}
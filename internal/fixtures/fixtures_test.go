@@ -0,0 +1,41 @@
+package fixtures
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/nickwallen/mocksie/internal/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Mocks_UpToDate(t *testing.T) {
+	// The options with which each mock is generated by go generate
+	mocks := map[string]generator.Options{
+		"mock_store_test.go": {
+			Returns: true, Expect: true, Delegate: true, Golden: true, Order: true, Context: true,
+			Faults: true, Wait: true, Gates: true, Reset: true, CallLog: true,
+		},
+		"mock_notifier_test.go": {Order: true, Reset: true},
+	}
+	for path, opts := range mocks {
+		t.Run(path, func(t *testing.T) {
+			existing, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			header, ok := generator.ParseHeader(existing)
+			require.True(t, ok)
+
+			// Regenerate the mock, which should be unchanged
+			found, err := parser.FindInterfaceIn(header.Source, header.Interface)
+			require.NoError(t, err)
+			opts.Header = header.Header
+			var regenerated bytes.Buffer
+			gen, err := generator.New(&regenerated, opts)
+			require.NoError(t, err)
+			err = gen.GenerateMock(found)
+			require.NoError(t, err)
+			require.Equal(t, string(existing), regenerated.String(), "regenerate the mocks with: go generate")
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Gates(t *testing.T) {
+	ctx := context.Background()
+	m := &mockStore{DoPut: func(context.Context, string, string) error { return nil }}
+	require.Nil(t, m.PutBlocked())
+	release := m.BlockPut()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = m.Put(ctx, "key", "value")
+	}()
+
+	// The call is blocked, so it is not recorded
+	<-m.PutBlocked()
+	require.Empty(t, m.PutCalls())
+
+	// Until it is released, which can be done more than once
+	release()
+	release()
+	<-done
+	require.Len(t, m.PutCalls(), 1)
+	require.Nil(t, m.PutBlocked())
+}
@@ -0,0 +1,65 @@
+package fixtures
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// goldenFile contains the calls recorded by Test_Golden_Record.
+const goldenFile = "testdata/store.json"
+
+func Test_Golden_Record(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, goldenFile)
+
+	// Record the calls made to the delegate
+	ctx := context.Background()
+	m := newMockStoreRecorder(&mapStore{})
+	err = m.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	value, err := m.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+	_, err = m.Get(ctx, "missing")
+	require.Equal(t, errNotFound, err)
+
+	// And save them, creating the directory
+	err = m.SaveGolden(path)
+	require.NoError(t, err)
+	recorded, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	expected, err := ioutil.ReadFile(goldenFile)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(recorded))
+}
+
+func Test_Golden_Replay(t *testing.T) {
+	ctx := context.Background()
+	m, err := newMockStoreReplayer(goldenFile)
+	require.NoError(t, err)
+
+	// The calls are matched by their arguments, rather than their order
+	_, err = m.Get(ctx, "missing")
+	require.EqualError(t, err, errNotFound.Error())
+	value, err := m.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+	err = m.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	require.Len(t, m.GetCalls(), 2)
+
+	// Each recorded call is replayed once
+	require.PanicsWithValue(t, `mockStore: no recorded call to Get matches the arguments {"Key":"key"}`, func() {
+		_, _ = m.Get(ctx, "key")
+	})
+
+	_, err = newMockStoreReplayer("testdata/missing.json")
+	require.True(t, os.IsNotExist(err))
+}
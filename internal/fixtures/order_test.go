@@ -0,0 +1,39 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Order_SharedSequence(t *testing.T) {
+	ctx := context.Background()
+	sequence := new(uint64)
+	s := newMockStoreFrom(&mapStore{})
+	s.Sequence = sequence
+	n := &mockNotifier{DoNotify: func(string) {}, Sequence: sequence}
+	err := s.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	n.Notify("key")
+	_, err = s.Get(ctx, "key")
+	require.NoError(t, err)
+
+	put, notify, get := s.PutCalls()[0], n.NotifyCalls()[0], s.GetCalls()[0]
+	require.NoError(t, mockStoreInOrder(put, notify, get))
+	require.Error(t, mockStoreInOrder(notify, put, get))
+	require.Error(t, mockNotifierInOrder(get, notify))
+}
+
+func Test_Order_DifferentSequences(t *testing.T) {
+	ctx := context.Background()
+	s := newMockStoreFrom(&mapStore{})
+	n := &mockNotifier{DoNotify: func(string) {}}
+	err := s.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	n.Notify("key")
+
+	// The calls made to mocks that do not share a sequence cannot be ordered
+	err = mockStoreInOrder(s.PutCalls()[0], n.NotifyCalls()[0])
+	require.EqualError(t, err, "call 0 and call 1 were numbered by different sequences; share one through the Sequence of each mock")
+}
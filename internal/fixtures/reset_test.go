@@ -0,0 +1,48 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Reset(t *testing.T) {
+	ctx := context.Background()
+	m := newMockStoreFrom(&mapStore{})
+	m.GetReturns("queued", nil)
+	m.OnPut(ctx, "key", "value").Return(errors.New("expected"))
+	m.BlockGet()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = m.Get(ctx, "key")
+	}()
+	<-m.GetBlocked()
+
+	// Reset releases the blocked call, which is forwarded to the delegate as the queued results are cleared
+	m.Reset()
+	<-done
+	require.Nil(t, m.GetBlocked())
+	require.Len(t, m.GetCalls(), 1)
+	require.Equal(t, errNotFound, m.GetCalls()[0].Results.R1)
+
+	// As are the expectations
+	snapshot := m.Snapshot()
+	err := m.Put(ctx, "key", "value")
+	require.NoError(t, err)
+	value, err := m.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+	require.Len(t, m.GetCalls(), 2)
+
+	// The snapshot is unaffected by later calls
+	require.Len(t, snapshot.Get, 1)
+	require.Empty(t, snapshot.Put)
+	require.Len(t, m.Snapshot().Put, 1)
+
+	m.ResetCalls()
+	require.Empty(t, m.GetCalls())
+	require.Empty(t, m.PutCalls())
+}
@@ -0,0 +1,55 @@
+package fixtures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Returns_WhenExhausted(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		exhausted mockStoreExhausted
+		expected  []string
+		panics    bool
+	}{
+		{
+			name:      "fallback",
+			exhausted: mockStoreFallback,
+			expected:  []string{"first", "second", "do"},
+		},
+		{
+			name:      "repeat-last",
+			exhausted: mockStoreRepeatLast,
+			expected:  []string{"first", "second", "second"},
+		},
+		{
+			name:      "fail",
+			exhausted: mockStoreFail,
+			expected:  []string{"first", "second"},
+			panics:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &mockStore{
+				DoGet:         func(context.Context, string) (string, error) { return "do", nil },
+				WhenExhausted: test.exhausted,
+			}
+			m.GetReturns("first", nil).GetReturns("second", nil)
+
+			for _, expected := range test.expected {
+				value, err := m.Get(ctx, "key")
+				require.NoError(t, err)
+				require.Equal(t, expected, value)
+			}
+			if test.panics {
+				require.PanicsWithValue(t, "mockStore: the results queued for Get are exhausted", func() {
+					_, _ = m.Get(ctx, "key")
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WaitFor(t *testing.T) {
+	ctx := context.Background()
+	m := &mockStore{DoPut: func(context.Context, string, string) error { return nil }}
+	called := m.PutCalled()
+
+	// No call has been made
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := m.WaitForPutCalls(timeout, 1)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Contains(t, err.Error(), "waiting for 1 calls to Put, but 0 were made")
+
+	// Until one is made asynchronously
+	go func() { _ = m.Put(ctx, "key", "value") }()
+	<-called
+	err = m.WaitForPutCalls(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, m.PutCalls(), 1)
+}
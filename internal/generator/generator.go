@@ -2,25 +2,300 @@ package generator
 
 import (
 	"bytes"
-	"html/template"
+	"fmt"
+	"go/token"
 	"io"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Masterminds/sprig"
 	"github.com/nickwallen/mocksie/internal"
 	"golang.org/x/tools/imports"
 )
 
+// DefaultMockName is the template that defines the name of a mock by default.
+const DefaultMockName = "mock{{ .Name | upperFirst }}"
+
+// commonInitialisms are the initialisms that are kept in a consistent case when
+// the first letter of a name is changed to upper case.
+var commonInitialisms = []string{
+	"acl", "api", "ascii", "cpu", "css", "dns", "eof", "guid", "html", "http", "https",
+	"id", "ip", "json", "lhs", "qps", "ram", "rhs", "rpc", "sla", "smtp", "sql", "ssh",
+	"tcp", "tls", "ttl", "udp", "ui", "uid", "uuid", "uri", "url", "utf8", "vm", "xml",
+	"xmpp", "xsrf", "xss",
+}
+
+// stdImports are the packages of the standard library used by the templates, other
+// than math/rand which is always imported as mathrand.
+var stdImports = []string{
+	"bytes", "context", "encoding/json", "errors", "fmt", "io/ioutil", "os", "path/filepath", "reflect",
+	"sort", "strings", "sync", "sync/atomic", "testing", "time",
+}
+
+// Options defines the optional features that are generated for a mock.
+type Options struct {
+	// Returns generates helpers that queue the results returned by each method.
+	Returns bool
+
+	// Calls records the calls made to each method.
+	Calls bool
+
+	// Delegate forwards the calls to each method without a Do function to
+	// an implementation of the interface. Implies Calls.
+	Delegate bool
+
+	// Expect generates helpers that define the results of each method when called
+	// with matching arguments.
+	Expect bool
+
+	// Order numbers the calls made to the mock, or to every mock that shares
+	// its Sequence, so that their order can be verified. Implies Calls.
+	Order bool
+
+	// Context makes each method, whose first parameter is a context and last
+	// result is an error, return the error of the context once it is done.
+	Context bool
+
+	// Faults generates helpers that inject latency and errors into each method
+	// that returns an error.
+	Faults bool
+
+	// Wait generates helpers that wait for the calls made to each method. Implies
+	// Calls.
+	Wait bool
+
+	// Gates generates helpers that block the calls made to each method until
+	// they are released.
+	Gates bool
+
+	// Reset generates helpers that reset, and take a snapshot of, the state of
+	// the mock. Implies Calls.
+	Reset bool
+
+	// CallLog generates helpers that render the calls made to the mock and log
+	// them when a test fails. Implies Order.
+	CallLog bool
+
+	// Golden records the calls made to a delegate implementation so that they
+	// can be saved to, and replayed from, a golden file. Implies Calls and Delegate.
+	Golden bool
+
+	// NoAssert skips the compile-time assertion that the mock implements the interface.
+	NoAssert bool
+
+	// MockName is the template that defines the name of the mock. Defaults to
+	// DefaultMockName.
+	MockName string
+
+	// Templates is a directory or file containing templates that override the
+	// built-in templates of the same name.
+	Templates string
+
+	// Export ensures that the name of the mock is exported.
+	Export bool
+
+	// Header is the provenance recorded in the header of the mock.
+	Header Header
+}
+
+// Header is the provenance recorded in the generated code header of a mock.
+type Header struct {
+	// Version is the version of mocksie that generated the mock.
+	Version string
+
+	// Source is the file containing the interface definition.
+	Source string
+
+	// Command is the command line used to regenerate the mock.
+	Command string
+}
+
 // Generator generates the mock implementation of an Interface.
 type Generator struct {
-	writer io.Writer
-	tmpl   *template.Template
+	writer   io.Writer
+	opts     Options
+	tmpl     *template.Template
+	mockName *template.Template
+}
+
+// header is the template of the header of a generated mock, which is kept apart from
+// the templates that can be overridden.
+var header = template.Must(template.New("header").Parse(headerTemplate))
+
+// TemplateDataVersion is the version of the TemplateData that is passed to the
+// templates. It is incremented whenever TemplateData changes in a way that may
+// break existing templates.
+const TemplateDataVersion = 1
+
+// TemplateData is the data that is passed to the templates. The fields of the
+// Interface; Name, Package, Imports, TypeParams, Methods and Position, are
+// promoted so that templates can refer to them directly.
+type TemplateData struct {
+	*mocksie.Interface
+
+	// Version is the version of the TemplateData; TemplateDataVersion.
+	Version int
+
+	// Options are the options used to generate the mock.
+	Options Options
+
+	// MockName is the name of the mock.
+	MockName string
+}
+
+// StdImports returns the packages of the standard library used by the templates, except
+// for those whose name is taken by one of the imports of the interface.
+func (d TemplateData) StdImports() []mocksie.Import {
+	taken := make(map[string]bool, len(d.Imports))
+	for _, imp := range d.Imports {
+		taken[path.Base(imp.Path)] = true
+	}
+	imports := make([]mocksie.Import, 0, len(stdImports))
+	for _, std := range stdImports {
+		if !taken[path.Base(std)] {
+			imports = append(imports, mocksie.Import{Path: std})
+		}
+	}
+	return imports
+}
+
+// Stateful returns true if the mock needs to guard its state with a mutex.
+func (d TemplateData) Stateful() bool {
+	return d.Options.Returns || d.Options.Calls || d.Options.Expect || d.Options.Context || d.Options.Faults ||
+		d.Options.Gates
+}
+
+// HasMethod returns true if the interface has a method, whether mocked or ignored,
+// with the given name.
+func (d TemplateData) HasMethod(name string) bool {
+	for _, method := range append(d.Methods[:len(d.Methods):len(d.Methods)], d.Ignored...) {
+		if method.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// helpers returns the names of the fields and methods of the mock, other than those
+// implementing the interface, along with the option that generates each of them.
+func (d TemplateData) helpers() map[string]string {
+	helpers := make(map[string]string)
+	add := func(opt string, names ...string) {
+		for _, name := range names {
+			helpers[name] = opt
+		}
+	}
+	for _, method := range d.Methods {
+		add("mock", "Do"+method.Name)
+		if d.Options.Returns && len(method.Results) > 0 {
+			add("returns", "returns"+method.Name, method.Name+"Returns", "next"+method.Name)
+		}
+		if d.Options.Calls {
+			add("calls", "calls"+method.Name, "invoke"+method.Name, method.Name+"Calls")
+		}
+		if d.Options.Golden {
+			add("golden", "record"+method.Name, "replay"+method.Name)
+		}
+		if d.Options.Expect {
+			add("expect", "expect"+method.Name, "On"+method.Name, "On"+method.Name+"Match", "expect"+method.Name+"Call",
+				"match"+method.Name)
+		}
+		if d.Options.Context && honorsContext(method) {
+			add("context", "blocks"+method.Name, method.Name+"BlocksUntilDone")
+		}
+		if d.Options.Faults && returnsError(method) {
+			add("faults", "faults"+method.Name, method.Name+"Faults")
+		}
+		if d.Options.Wait {
+			add("wait", "notify"+method.Name, method.Name+"Called", "WaitFor"+method.Name+"Calls")
+		}
+		if d.Options.Gates {
+			add("gates", "gate"+method.Name, "Block"+method.Name, method.Name+"Blocked")
+		}
+	}
+	if d.Stateful() {
+		add("mock", "mu")
+	}
+	if d.Options.Delegate {
+		add("delegate", "Delegate")
+	}
+	if d.Options.Order {
+		add("order", "Sequence", "sequence")
+	}
+	if d.Options.Returns {
+		add("returns", "WhenExhausted")
+	}
+	if d.Options.Golden {
+		add("golden", "recording", "replaying", "golden", "SaveGolden")
+	}
+	if d.Options.Reset {
+		add("reset", "Reset", "ResetCalls", "Snapshot")
+	}
+	if d.Options.CallLog {
+		add("call-log", "CallLog")
+	}
+	return helpers
+}
+
+// checkHelpers returns an error if a method of the interface has the same name as one
+// of the fields or methods generated for the mock.
+func (d TemplateData) checkHelpers() error {
+	helpers := d.helpers()
+	for _, method := range append(d.Methods[:len(d.Methods):len(d.Methods)], d.Ignored...) {
+		opt, ok := helpers[method.Name]
+		if !ok {
+			continue
+		}
+		if opt == "mock" {
+			return fmt.Errorf("method %s of %s clashes with a field of the mock", method.Name, d.Name)
+		}
+		return fmt.Errorf("method %s of %s clashes with a helper generated by --%s", method.Name, d.Name, opt)
+	}
+	return nil
+}
+
+// Constructor returns the name of a function that constructs the mock. The function
+// is only exported if the mock is exported.
+func (d TemplateData) Constructor(suffix string) string {
+	if token.IsExported(d.MockName) {
+		return "New" + d.MockName + suffix
+	}
+	return "new" + upperFirst(d.MockName) + suffix
 }
 
 // New create a new Generator.
-func New(writer io.Writer) (*Generator, error) {
+func New(writer io.Writer, opts Options) (*Generator, error) {
+	if len(opts.MockName) == 0 {
+		opts.MockName = DefaultMockName
+	}
+	if opts.Golden {
+		opts.Delegate = true
+	}
+	if opts.CallLog {
+		opts.Order = true
+	}
+	if opts.Delegate || opts.Order || opts.Wait || opts.Reset {
+		opts.Calls = true
+	}
+	mockName, err := template.New("mock-name").Funcs(sprig.TxtFuncMap()).Funcs(funcMap()).Parse(opts.MockName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock name template: %w", err)
+	}
+	tmpl := initTemplates()
+	if len(opts.Templates) > 0 {
+		err = overrideTemplates(tmpl, opts.Templates)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &Generator{
-		writer: writer,
-		tmpl:   initTemplates(),
+		writer:   writer,
+		opts:     opts,
+		tmpl:     tmpl,
+		mockName: mockName,
 	}, nil
 }
 
@@ -28,8 +303,30 @@ func New(writer io.Writer) (*Generator, error) {
 func (g *Generator) GenerateMock(iface *mocksie.Interface) error {
 	var out bytes.Buffer
 
-	// Generate the mocks
-	err := g.tmpl.ExecuteTemplate(&out, "base", iface)
+	// Name the mock
+	mockName, err := g.MockName(iface)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the helpers generated for the mock do not clash with the interface
+	data := TemplateData{
+		Interface: iface,
+		Version:   TemplateDataVersion,
+		Options:   g.opts,
+		MockName:  mockName,
+	}
+	err = data.checkHelpers()
+	if err != nil {
+		return err
+	}
+
+	// Generate the mocks, starting with the header
+	err = header.Execute(&out, data)
+	if err != nil {
+		return err
+	}
+	err = g.tmpl.ExecuteTemplate(&out, "base", data)
 	if err != nil {
 		return err
 	}
@@ -45,14 +342,168 @@ func (g *Generator) GenerateMock(iface *mocksie.Interface) error {
 	return err
 }
 
+// MockName returns the name of the mock for an Interface.
+func (g *Generator) MockName(iface *mocksie.Interface) (string, error) {
+	var out bytes.Buffer
+	err := g.mockName.Execute(&out, iface)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(out.String())
+	if g.opts.Export {
+		name = upperFirst(name)
+	}
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("invalid mock name %q", name)
+	}
+	return name, nil
+}
+
 // initTemplates initialize the templates that are used to generate the mocks.
 func initTemplates() *template.Template {
-	tmpl := template.New("").Funcs(sprig.FuncMap())
+	tmpl := template.New("").Funcs(sprig.TxtFuncMap()).Funcs(funcMap())
 	tmpl = template.Must(tmpl.New("base").Parse(baseTemplate))
 	tmpl = template.Must(tmpl.New("imports").Parse(importsTemplate))
+	tmpl = template.Must(tmpl.New("assert").Parse(assertTemplate))
 	tmpl = template.Must(tmpl.New("methods").Parse(methodsTemplate))
+	tmpl = template.Must(tmpl.New("ignored").Parse(ignoredTemplate))
 	tmpl = template.Must(tmpl.New("declare-params").Parse(declareParamsTemplate))
 	tmpl = template.Must(tmpl.New("use-params").Parse(useParamsTemplate))
+	tmpl = template.Must(tmpl.New("declare-type-params").Parse(declareTypeParamsTemplate))
+	tmpl = template.Must(tmpl.New("use-type-params").Parse(useTypeParamsTemplate))
 	tmpl = template.Must(tmpl.New("results").Parse(resultsTemplate))
+	tmpl = template.Must(tmpl.New("types").Parse(typesTemplate))
+	tmpl = template.Must(tmpl.New("delegate").Parse(delegateTemplate))
+	tmpl = template.Must(tmpl.New("returns").Parse(returnsTemplate))
+	tmpl = template.Must(tmpl.New("calls").Parse(callsTemplate))
+	tmpl = template.Must(tmpl.New("expect").Parse(expectTemplate))
+	tmpl = template.Must(tmpl.New("order").Parse(orderTemplate))
+	tmpl = template.Must(tmpl.New("context").Parse(contextTemplate))
+	tmpl = template.Must(tmpl.New("faults").Parse(faultsTemplate))
+	tmpl = template.Must(tmpl.New("wait").Parse(waitTemplate))
+	tmpl = template.Must(tmpl.New("gates").Parse(gatesTemplate))
+	tmpl = template.Must(tmpl.New("reset").Parse(resetTemplate))
+	tmpl = template.Must(tmpl.New("call-log").Parse(callLogTemplate))
+	tmpl = template.Must(tmpl.New("golden").Parse(goldenTemplate))
 	return tmpl
 }
+
+// funcMap returns the functions, in addition to those provided by sprig, that
+// are available to the templates.
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		"argField":      argField,
+		"argList":       argList,
+		"errResultList": errResultList,
+		"honorsContext": honorsContext,
+		"local":         local,
+		"resultField":   resultField,
+		"returnsError":  returnsError,
+		"resultList":    resultList,
+		"upperFirst":    upperFirst,
+		"verb":          verb,
+	}
+}
+
+// argField returns the name of the struct field that holds a method argument.
+func argField(index int, param mocksie.Param) string {
+	if len(param.Name) > 0 {
+		return upperFirst(param.Name)
+	}
+	return fmt.Sprintf("Arg%d", index)
+}
+
+// argList returns the comma separated list of struct fields that hold the
+// arguments of a method.
+func argList(prefix string, params []mocksie.Param) string {
+	fields := make([]string, 0, len(params))
+	for i, param := range params {
+		fields = append(fields, prefix+"."+argField(i, param))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// resultField returns the name of the struct field that holds a method result.
+func resultField(index int, result mocksie.Result) string {
+	if len(result.Name) > 0 {
+		return upperFirst(result.Name)
+	}
+	return fmt.Sprintf("R%d", index)
+}
+
+// resultList returns the comma separated list of struct fields that hold the
+// results of a method.
+func resultList(prefix string, results []mocksie.Result) string {
+	fields := make([]string, 0, len(results))
+	for i, result := range results {
+		fields = append(fields, prefix+"."+resultField(i, result))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// errResultList returns the comma separated list of struct fields that hold the
+// results of a method, except for the last which is replaced by an error.
+func errResultList(prefix string, results []mocksie.Result, err string) string {
+	fields := make([]string, 0, len(results))
+	for i := 0; i < len(results)-1; i++ {
+		fields = append(fields, prefix+"."+resultField(i, results[i]))
+	}
+	return strings.Join(append(fields, err), ", ")
+}
+
+// local returns the name of a local variable declared by the mock implementation
+// of a method, which must not clash with the names of its parameters or results.
+func local(method mocksie.Method, name string) string {
+	declared := make(map[string]bool)
+	for _, param := range method.Params {
+		declared[param.Name] = true
+	}
+	for _, result := range method.Results {
+		declared[result.Name] = true
+	}
+	for declared[name] {
+		name = "mocksie" + upperFirst(name)
+	}
+	return name
+}
+
+// honorsContext returns true if the first parameter of a method is a context and
+// its last result is an error.
+func honorsContext(method mocksie.Method) bool {
+	return len(method.Params) > 0 && method.Params[0].Type == "context.Context" && returnsError(method)
+}
+
+// returnsError returns true if the last result of a method is an error.
+func returnsError(method mocksie.Method) bool {
+	return len(method.Results) > 0 && method.Results[len(method.Results)-1].Type == "error"
+}
+
+// verb returns the fmt verb used to render a value of the given type. Strings are
+// quoted so that they are unambiguous.
+func verb(typ string) string {
+	if typ == "string" {
+		return "%q"
+	}
+	return "%+v"
+}
+
+// upperFirst returns the name with its first letter in upper case. A common
+// initialism at the start of the name is entirely upper cased; httpClient
+// becomes HTTPClient rather than HttpClient.
+func upperFirst(name string) string {
+	if len(name) == 0 {
+		return name
+	}
+	for _, initialism := range commonInitialisms {
+		if !strings.HasPrefix(name, initialism) {
+			continue
+		}
+		// The initialism must be followed by the start of a new word
+		rest := name[len(initialism):]
+		if next, _ := utf8.DecodeRuneInString(rest); len(rest) == 0 || !unicode.IsLower(next) {
+			return strings.ToUpper(initialism) + rest
+		}
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
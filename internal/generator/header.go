@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// headerPattern matches the first line of the header of a generated mock and
+// captures the version of mocksie, if any.
+var headerPattern = regexp.MustCompile(`^// Code generated by mocksie(?: (\S+))?\. DO NOT EDIT\.$`)
+
+// legacyPattern matches the comment that documents a mock generated before mocks had a header.
+var legacyPattern = regexp.MustCompile(`^// \w+ ia a mock implementation of the \w+ interface\.$`)
+
+// ParsedHeader is the header parsed from a generated mock.
+type ParsedHeader struct {
+	Header
+
+	// Interface is the name of the interface that the mock implements.
+	Interface string
+
+	// Package is the package in which the interface is defined.
+	Package string
+}
+
+// ParseHeader parses the header of a generated mock. It returns false if the source
+// code does not start with a header generated by mocksie.
+func ParseHeader(src []byte) (ParsedHeader, bool) {
+	var header ParsedHeader
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	if !scanner.Scan() {
+		return header, false
+	}
+	match := headerPattern.FindStringSubmatch(scanner.Text())
+	if match == nil {
+		return header, false
+	}
+	header.Version = match[1]
+
+	// The header ends with the first line that is not a comment
+	for scanner.Scan() && strings.HasPrefix(scanner.Text(), "//") {
+		key, value, ok := cut(strings.TrimPrefix(scanner.Text(), "//"), ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Interface":
+			header.Interface = strings.TrimSpace(value)
+		case "Package":
+			header.Package = strings.TrimSpace(value)
+		case "Source":
+			header.Source = strings.TrimSpace(value)
+		case "Command":
+			header.Command = strings.TrimSpace(value)
+		}
+	}
+	return header, true
+}
+
+// IsLegacyMock returns true if the source code is a mock generated by a version of mocksie
+// that did not write a header. These start with the package clause, and the first comment
+// documents the mock.
+func IsLegacyMock(src []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "package ") {
+		return false
+	}
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "//") {
+			return legacyPattern.MatchString(scanner.Text())
+		}
+	}
+	return false
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
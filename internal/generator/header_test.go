@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		expected  ParsedHeader
+		generated bool
+	}{
+		{
+			name: "full",
+			src: `// Code generated by mocksie v1.2.3. DO NOT EDIT.
+//
+// Interface: greeter
+// Package:   main
+// Source:    greeter.go
+// Command:   mocksie --in greeter.go --name greeter --mock-name '{{ .Name }}Mock'
+
+package main
+`,
+			expected: ParsedHeader{
+				Header: Header{
+					Version: "v1.2.3",
+					Source:  "greeter.go",
+					Command: "mocksie --in greeter.go --name greeter --mock-name '{{ .Name }}Mock'",
+				},
+				Interface: "greeter",
+				Package:   "main",
+			},
+			generated: true,
+		},
+		{
+			name: "minimal",
+			src: `// Code generated by mocksie. DO NOT EDIT.
+
+package main
+`,
+			expected:  ParsedHeader{},
+			generated: true,
+		},
+		{
+			name: "other-generator",
+			src: `// Code generated by mockgen. DO NOT EDIT.
+
+package main
+`,
+			generated: false,
+		},
+		{
+			name:      "hand-written",
+			src:       "package main\n",
+			generated: false,
+		},
+		{
+			name:      "empty",
+			src:       "",
+			generated: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header, generated := ParseHeader([]byte(test.src))
+			require.Equal(t, test.generated, generated)
+			require.Equal(t, test.expected, header)
+		})
+	}
+}
+
+func Test_IsLegacyMock(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		legacy bool
+	}{
+		{
+			name:   "legacy",
+			src:    "package main\n\nimport (\n\t\"io\"\n)\n\n// mockGreeter ia a mock implementation of the greeter interface.\ntype mockGreeter struct {}\n",
+			legacy: true,
+		},
+		{
+			name:   "hand-written",
+			src:    "package main\n\n// greeter says hello.\ntype greeter struct {}\n",
+			legacy: false,
+		},
+		{
+			name:   "not-package-first",
+			src:    "// mockGreeter ia a mock implementation of the greeter interface.\npackage main\n",
+			legacy: false,
+		},
+		{
+			name:   "empty",
+			src:    "",
+			legacy: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.legacy, IsLegacyMock([]byte(test.src)))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+)
+
+// templateExt is the file extension of a template that overrides a built-in template.
+const templateExt = ".tmpl"
+
+// overrideTemplates overrides the built-in templates with those found at a path. The
+// path is either a directory containing one file per template, like methods.tmpl, or
+// a single file that defines one or more templates like {{ define "methods" }}.
+func overrideTemplates(tmpl *template.Template, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return overrideFromDir(tmpl, path)
+	}
+	return overrideFromFile(tmpl, path)
+}
+
+// overrideFromDir overrides the built-in templates with the files in a directory.
+func overrideFromDir(tmpl *template.Template, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return err
+	}
+	known := knownTemplates(tmpl)
+	if len(files) == 0 {
+		return fmt.Errorf("%s: no %s files found; expected one of %s", dir, templateExt, known)
+	}
+	for _, file := range files {
+		// The name of the file must match the name of a built-in template
+		name := strings.TrimSuffix(filepath.Base(file), templateExt)
+		if tmpl.Lookup(name) == nil {
+			return fmt.Errorf("%s: %q is not a template; expected one of %s", file, name, known)
+		}
+		text, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		_, err = tmpl.New(name).Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// overrideFromFile overrides the built-in templates with those defined in a file.
+func overrideFromFile(tmpl *template.Template, file string) error {
+	text, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	// Find the templates defined in the file
+	defined, err := template.New(file).Funcs(sprig.TxtFuncMap()).Funcs(funcMap()).Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	overrides := 0
+	for _, t := range defined.Templates() {
+		if t.Name() != file && tmpl.Lookup(t.Name()) != nil {
+			overrides++
+		}
+	}
+	if overrides == 0 {
+		return fmt.Errorf("%s: does not override any templates; expected one of %s", file, knownTemplates(tmpl))
+	}
+
+	_, err = tmpl.New(file).Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// knownTemplates returns the sorted, comma separated names of the built-in templates.
+func knownTemplates(tmpl *template.Template) string {
+	names := make([]string, 0)
+	for _, t := range tmpl.Templates() {
+		if len(t.Name()) > 0 {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwallen/mocksie/internal"
+	"github.com/stretchr/testify/require"
+)
+
+// overrideIface is the interface that the overridden templates generate a mock for.
+var overrideIface = &mocksie.Interface{
+	Name:    "greeter",
+	Package: "main",
+	Methods: []mocksie.Method{
+		{
+			Name: "SayHello",
+			Params: []mocksie.Param{
+				{Name: "name", Type: "string"},
+			},
+			Results: []mocksie.Result{
+				{Name: "", Type: "string"},
+				{Name: "", Type: "error"},
+			},
+		},
+	},
+}
+
+// overrideMethods is a template that overrides the built-in "methods" template.
+const overrideMethods = `
+{{- range .Methods }}
+// {{ .Name }} logs each call. Version {{ $.Version }}.
+func (m *{{ $.MockName }}) {{ .Name }}({{ template "declare-params" . }}) {{ template "results" . }} {
+    log.Printf("{{ .Name }}")
+    return m.Do{{ .Name }}({{ template "use-params" . }})
+}
+{{ end }}
+`
+
+// overrideExpected is the mock that is expected when the "methods" template is overridden.
+const overrideExpected = `
+// Code generated by mocksie. DO NOT EDIT.
+//
+// Interface: greeter
+// Package:   main
+
+package main
+
+import "log"
+
+// mockGreeter ia a mock implementation of the greeter interface.
+type mockGreeter struct {
+	DoSayHello func(name string) (string, error)
+}
+
+// Ensure that mockGreeter implements the greeter interface.
+var _ greeter = (*mockGreeter)(nil)
+
+// SayHello logs each call. Version 1.
+func (m *mockGreeter) SayHello(name string) (string, error) {
+	log.Printf("SayHello")
+	return m.DoSayHello(name)
+}
+`
+
+func Test_Generator_OverrideTemplates_Dir(t *testing.T) {
+	var out bytes.Buffer
+
+	// Create a directory containing the overridden template
+	dir, err := ioutil.TempDir("", "templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "methods.tmpl"), []byte(overrideMethods), 0600)
+	require.NoError(t, err)
+
+	// Generate the mock
+	gen, err := New(&out, Options{Templates: dir})
+	require.NoError(t, err)
+	err = gen.GenerateMock(overrideIface)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimPrefix(overrideExpected, "\n"), out.String())
+}
+
+func Test_Generator_OverrideTemplates_File(t *testing.T) {
+	var out bytes.Buffer
+
+	// Create a file that defines the overridden template
+	file, err := ioutil.TempFile("", "templates.tmpl")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	err = ioutil.WriteFile(file.Name(), []byte(`{{ define "methods" }}`+overrideMethods+`{{ end }}`), 0600)
+	require.NoError(t, err)
+
+	// Generate the mock
+	gen, err := New(&out, Options{Templates: file.Name()})
+	require.NoError(t, err)
+	err = gen.GenerateMock(overrideIface)
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimPrefix(overrideExpected, "\n"), out.String())
+}
+
+func Test_Generator_OverrideTemplates_Header(t *testing.T) {
+	var out bytes.Buffer
+
+	// Create a directory containing an overridden base template
+	dir, err := ioutil.TempDir("", "templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	base := "package {{ .Package }}\n\n// {{ .MockName }} is a mock.\ntype {{ .MockName }} struct{}\n"
+	err = ioutil.WriteFile(filepath.Join(dir, "base.tmpl"), []byte(base), 0600)
+	require.NoError(t, err)
+
+	// The header is generated regardless
+	gen, err := New(&out, Options{Templates: dir, Header: Header{Version: "v1.2.3"}})
+	require.NoError(t, err)
+	err = gen.GenerateMock(overrideIface)
+	require.NoError(t, err)
+	header, ok := ParseHeader(out.Bytes())
+	require.True(t, ok)
+	require.Equal(t, "v1.2.3", header.Version)
+	require.Equal(t, "greeter", header.Interface)
+	require.Contains(t, out.String(), "// mockGreeter is a mock.\n")
+}
+
+func Test_Generator_OverrideTemplates_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string // An empty filename writes the template to a file, rather than a directory.
+		text     string
+		err      string
+	}{
+		{
+			name:     "dir-unknown-template",
+			filename: "method.tmpl",
+			text:     overrideMethods,
+			err:      `"method" is not a template; expected one of assert, base,`,
+		},
+		{
+			name:     "dir-parse-error",
+			filename: "methods.tmpl",
+			text:     `{{ range .Methods }}`,
+			err:      "methods.tmpl: template: methods:1: unexpected EOF",
+		},
+		{
+			name: "file-no-overrides",
+			text: `{{ define "method" }}{{ end }}`,
+			err:  "does not override any templates; expected one of assert, base,",
+		},
+		{
+			name: "file-parse-error",
+			text: `{{ define "methods" }}`,
+			err:  "unexpected EOF",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			// Create a directory to hold the templates
+			dir, err := ioutil.TempDir("", "templates")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			// Write the template
+			path := filepath.Join(dir, "templates.tmpl")
+			if len(test.filename) > 0 {
+				path = dir
+				err = ioutil.WriteFile(filepath.Join(dir, test.filename), []byte(test.text), 0600)
+			} else {
+				err = ioutil.WriteFile(path, []byte(test.text), 0600)
+			}
+			require.NoError(t, err)
+
+			// Create the generator
+			_, err = New(&out, Options{Templates: path})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func Test_Generator_OverrideTemplates_DoesNotExist(t *testing.T) {
+	var out bytes.Buffer
+	_, err := New(&out, Options{Templates: "/this/path/does/not/exist"})
+	require.Error(t, err)
+}
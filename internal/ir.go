@@ -0,0 +1,79 @@
+package mocksie
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+)
+
+// IRVersion is the version of the JSON schema of the intermediate representation.
+// It is incremented whenever the schema changes in a way that may break existing
+// documents or the tools that produce them.
+const IRVersion = 1
+
+// IR is a document containing the intermediate representation of an Interface.
+type IR struct {
+	Version   int        `json:"version"`
+	Interface *Interface `json:"interface"`
+}
+
+// EncodeIR writes the intermediate representation of an Interface as JSON.
+func EncodeIR(w io.Writer, iface *Interface) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(IR{Version: IRVersion, Interface: iface})
+}
+
+// DecodeIR reads the intermediate representation of an Interface from JSON.
+func DecodeIR(r io.Reader) (*Interface, error) {
+	var ir IR
+	err := json.NewDecoder(r).Decode(&ir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IR: %w", err)
+	}
+	if ir.Version != IRVersion {
+		return nil, fmt.Errorf("unsupported IR version %d; expected %d", ir.Version, IRVersion)
+	}
+	if ir.Interface == nil {
+		return nil, errors.New("invalid IR: no interface")
+	}
+	err = validateIR(ir.Interface)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IR: %w", err)
+	}
+	return ir.Interface, nil
+}
+
+// validateIR ensures that an Interface, which may have been produced by another
+// tool, can be mocked.
+func validateIR(iface *Interface) error {
+	if !token.IsIdentifier(iface.Name) {
+		return fmt.Errorf("interface name %q is not an identifier", iface.Name)
+	}
+	if !token.IsIdentifier(string(iface.Package)) {
+		return fmt.Errorf("package %q is not an identifier", iface.Package)
+	}
+	for _, typeParam := range iface.TypeParams {
+		if !token.IsIdentifier(typeParam.Name) || len(typeParam.Constraint) == 0 {
+			return fmt.Errorf("type parameter %q must be an identifier with a constraint", typeParam.Name)
+		}
+	}
+	for _, method := range append(iface.Methods[:len(iface.Methods):len(iface.Methods)], iface.Ignored...) {
+		if !token.IsIdentifier(method.Name) {
+			return fmt.Errorf("method name %q is not an identifier", method.Name)
+		}
+		for _, param := range method.Params {
+			if len(param.Type) == 0 {
+				return fmt.Errorf("method %s: parameter %q has no type", method.Name, param.Name)
+			}
+		}
+		for _, result := range method.Results {
+			if len(result.Type) == 0 {
+				return fmt.Errorf("method %s: result %q has no type", method.Name, result.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package mocksie
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IR_RoundTrip(t *testing.T) {
+	iface := &Interface{
+		Name:    "store",
+		Package: "main",
+		Imports: []Import{{Path: "context"}},
+		TypeParams: []TypeParam{
+			{Name: "V", Constraint: "any"},
+		},
+		Methods: []Method{
+			{
+				Name:     "Get",
+				Params:   []Param{{Name: "ctx", Type: "context.Context"}, {Name: "key", Type: "string"}},
+				Results:  []Result{{Name: "", Type: "V"}, {Name: "", Type: "error"}},
+				Position: Position{Filename: "store.go", Line: 4, Column: 2},
+			},
+		},
+		Ignored: []Method{
+			{
+				Name:     "Close",
+				Params:   []Param{},
+				Results:  []Result{{Name: "", Type: "error"}},
+				Position: Position{Filename: "store.go", Line: 5, Column: 2},
+			},
+		},
+		Position: Position{Filename: "store.go", Line: 3, Column: 6},
+	}
+
+	var out bytes.Buffer
+	err := EncodeIR(&out, iface)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out.String(), "{\n  \"version\": 1,\n  \"interface\": {\n    \"name\": \"store\","))
+
+	decoded, err := DecodeIR(&out)
+	require.NoError(t, err)
+	require.Equal(t, iface, decoded)
+}
+
+func Test_IR_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ir   string
+		err  string
+	}{
+		{
+			name: "not-json",
+			ir:   `interface greeter`,
+			err:  "invalid IR: invalid character 'i' looking for beginning of value",
+		},
+		{
+			name: "unsupported-version",
+			ir:   `{"version": 2, "interface": {"name": "greeter", "package": "main"}}`,
+			err:  "unsupported IR version 2; expected 1",
+		},
+		{
+			name: "no-interface",
+			ir:   `{"version": 1}`,
+			err:  "invalid IR: no interface",
+		},
+		{
+			name: "invalid-name",
+			ir:   `{"version": 1, "interface": {"name": "a greeter", "package": "main"}}`,
+			err:  `invalid IR: interface name "a greeter" is not an identifier`,
+		},
+		{
+			name: "no-package",
+			ir:   `{"version": 1, "interface": {"name": "greeter"}}`,
+			err:  `invalid IR: package "" is not an identifier`,
+		},
+		{
+			name: "param-without-type",
+			ir:   `{"version": 1, "interface": {"name": "greeter", "package": "main", "methods": [{"name": "SayHello", "params": [{"name": "name"}]}]}}`,
+			err:  `invalid IR: method SayHello: parameter "name" has no type`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeIR(strings.NewReader(test.ir))
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
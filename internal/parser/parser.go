@@ -6,6 +6,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,9 +15,27 @@ import (
 )
 
 var (
-	errNotFound = errors.New("interface not found")
+	// ErrNotFound is the error returned when the interface is not found.
+	ErrNotFound = errors.New("interface not found")
 )
 
+const (
+	// generateDirective annotates an interface that a mock is generated for.
+	generateDirective = "//mocksie:generate"
+
+	// ignoreDirective annotates a method that is ignored rather than mocked.
+	ignoreDirective = "//mocksie:ignore"
+)
+
+// Annotated is an interface that is annotated with a //mocksie:generate comment.
+type Annotated struct {
+	*mocksie.Interface
+
+	// Options are the options of the annotation; like out=mock.go. An option
+	// without a value is "true".
+	Options map[string]string
+}
+
 // Parser parses a file containing Go source code.
 type Parser struct {
 	filename string
@@ -40,11 +59,87 @@ func New(inFile string) (*Parser, error) {
 
 // FindInterface returns the interface with the given name.
 func (p *Parser) FindInterface(name string) (*mocksie.Interface, error) {
-	// Parse the file
-	f, err := parser.ParseFile(token.NewFileSet(), p.filename, nil, parser.AllErrors)
+	var found *mocksie.Interface
+	err := p.walkInterfaces(func(decl *ast.GenDecl, spec *ast.TypeSpec, typ *ast.InterfaceType, f *ast.File, fset *token.FileSet) (bool, error) {
+		// Is this the interface that we are looking for?
+		if name != spec.Name.String() {
+			return true, nil
+		}
+		iface, err := buildInterface(spec, typ, f, fset)
+		found = iface
+		return false, err
+	})
 	if err != nil {
 		return nil, err
 	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+// FindInterfaces returns the interfaces declared in the file, except for those that
+// use constructs that cannot be mocked.
+func (p *Parser) FindInterfaces() ([]*mocksie.Interface, error) {
+	found := make([]*mocksie.Interface, 0)
+	err := p.walkInterfaces(func(decl *ast.GenDecl, spec *ast.TypeSpec, typ *ast.InterfaceType, f *ast.File, fset *token.FileSet) (bool, error) {
+		iface, err := buildInterface(spec, typ, f, fset)
+		var unsupported *mocksie.UnsupportedError
+		if errors.As(err, &unsupported) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		found = append(found, iface)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// FindAnnotatedInterfaces returns the interfaces that are annotated with a
+// //mocksie:generate comment.
+func (p *Parser) FindAnnotatedInterfaces() ([]Annotated, error) {
+	annotated := make([]Annotated, 0)
+	err := p.walkInterfaces(func(decl *ast.GenDecl, spec *ast.TypeSpec, typ *ast.InterfaceType, f *ast.File, fset *token.FileSet) (bool, error) {
+		// The annotation is part of the declaration unless it declares many types
+		doc := spec.Doc
+		if doc == nil && !decl.Lparen.IsValid() {
+			doc = decl.Doc
+		}
+		args, ok := findDirective(doc, generateDirective)
+		if !ok {
+			return true, nil
+		}
+		options, err := buildOptions(args)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", fset.Position(spec.Name.Pos()), err)
+		}
+		iface, err := buildInterface(spec, typ, f, fset)
+		if err != nil {
+			return false, err
+		}
+		annotated = append(annotated, Annotated{Interface: iface, Options: options})
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return annotated, nil
+}
+
+// walkInterfaces parses the file and calls fn for each interface declared in it,
+// until fn returns false or an error.
+func (p *Parser) walkInterfaces(fn func(*ast.GenDecl, *ast.TypeSpec, *ast.InterfaceType, *ast.File, *token.FileSet) (bool, error)) error {
+	// Parse the file
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, p.filename, nil, parser.AllErrors|parser.ParseComments)
+	if err != nil {
+		return err
+	}
 
 	// Find any interfaces
 	for _, decl := range f.Decls {
@@ -72,29 +167,149 @@ func (p *Parser) FindInterface(name string) (*mocksie.Interface, error) {
 				continue
 			}
 
-			// Is this the interface that we are looking for?
-			if name == typeSpec.Name.String() {
-				return buildInterface(typeSpec.Name.String(), ifaceType, f)
+			next, err := fn(genDecl, typeSpec, ifaceType, f, fset)
+			if err != nil || !next {
+				return err
 			}
 		}
 	}
-	return nil, errNotFound
+	return nil
+}
+
+// findDirective returns the arguments of a directive, like //mocksie:generate, if
+// it is one of the comments.
+func findDirective(comments *ast.CommentGroup, directive string) (string, bool) {
+	if comments == nil {
+		return "", false
+	}
+	for _, comment := range comments.List {
+		if comment.Text == directive {
+			return "", true
+		}
+		if strings.HasPrefix(comment.Text, directive+" ") {
+			return strings.TrimPrefix(comment.Text, directive+" "), true
+		}
+	}
+	return "", false
+}
+
+// buildOptions Returns the options of an annotation; like out=mock.go.
+func buildOptions(args string) (map[string]string, error) {
+	options := make(map[string]string)
+	for _, field := range strings.Fields(args) {
+		key, value := field, "true"
+		if i := strings.Index(field, "="); i >= 0 {
+			key, value = field[:i], field[i+1:]
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("invalid option %q; expected key=value", field)
+		}
+		options[key] = value
+	}
+	return options, nil
+}
+
+// FindInterfaceIn returns the interface with the given name that is defined at a
+// path, which is either a file or a directory containing a package.
+func FindInterfaceIn(path, name string) (*mocksie.Interface, error) {
+	files, err := goFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		p, err := New(file)
+		if err != nil {
+			return nil, err
+		}
+		found, err := p.FindInterface(name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return found, err
+	}
+	return nil, ErrNotFound
+}
+
+// FindInterfacesIn returns the interfaces declared at a path, which is either a
+// file or a directory containing a package.
+func FindInterfacesIn(path string) ([]*mocksie.Interface, error) {
+	files, err := goFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]*mocksie.Interface, 0)
+	for _, file := range files {
+		p, err := New(file)
+		if err != nil {
+			return nil, err
+		}
+		ifaces, err := p.FindInterfaces()
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, ifaces...)
+	}
+	return found, nil
+}
+
+// goFiles returns the Go source files at a path, which is either a file or a
+// directory containing a package.
+func goFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return filepath.Glob(filepath.Join(path, "*.go"))
 }
 
 // buildInterface Returns an interface.
-func buildInterface(name string, typ *ast.InterfaceType, f *ast.File) (*mocksie.Interface, error) {
-	methods, err := buildMethods(typ)
+func buildInterface(spec *ast.TypeSpec, typ *ast.InterfaceType, f *ast.File, fset *token.FileSet) (*mocksie.Interface, error) {
+	methods, ignored, err := buildMethods(typ, fset)
 	if err != nil {
 		return nil, err
 	}
 	return &mocksie.Interface{
-		Name:    name,
-		Package: buildPackage(f),
-		Imports: buildImports(f),
-		Methods: methods,
+		Name:       spec.Name.String(),
+		Package:    buildPackage(f),
+		Imports:    buildImports(f),
+		TypeParams: buildTypeParams(spec),
+		Methods:    methods,
+		Ignored:    ignored,
+		Position:   buildPosition(spec.Name.Pos(), fset),
 	}, nil
 }
 
+// buildPosition Returns the position of a declaration.
+func buildPosition(pos token.Pos, fset *token.FileSet) mocksie.Position {
+	position := fset.Position(pos)
+	return mocksie.Position{
+		Filename: position.Filename,
+		Line:     position.Line,
+		Column:   position.Column,
+	}
+}
+
+// buildTypeParams Returns the type parameters of a generic interface.
+func buildTypeParams(spec *ast.TypeSpec) []mocksie.TypeParam {
+	if spec.TypeParams == nil {
+		return nil // Not a generic interface
+	}
+	typeParams := make([]mocksie.TypeParam, 0)
+	for _, field := range spec.TypeParams.List {
+		// Type parameters can share a constraint; [K, V any]
+		for _, name := range field.Names {
+			typeParams = append(typeParams, mocksie.TypeParam{
+				Name:       name.Name,
+				Constraint: types.ExprString(field.Type),
+			})
+		}
+	}
+	return typeParams
+}
+
 // buildPackage Returns the package defined within a file.
 func buildPackage(f *ast.File) mocksie.Package {
 	return mocksie.Package(f.Name.Name)
@@ -111,13 +326,14 @@ func buildImports(f *ast.File) []mocksie.Import {
 	return imports
 }
 
-// buildMethods Returns the methods of an interface.
-func buildMethods(typ *ast.InterfaceType) ([]mocksie.Method, error) {
+// buildMethods Returns the methods of an interface, and those that are ignored.
+func buildMethods(typ *ast.InterfaceType, fset *token.FileSet) ([]mocksie.Method, []mocksie.Method, error) {
 	methods := make([]mocksie.Method, 0)
+	var ignored []mocksie.Method
 	for _, field := range typ.Methods.List {
-		// Expect the method to be named
+		// Expect the method to be named, rather than an embedded interface
 		if len(field.Names) == 0 {
-			continue
+			return nil, nil, unsupported("embedded interface "+types.ExprString(field.Type), field.Type.Pos(), fset)
 		}
 
 		// Expect a function type
@@ -126,26 +342,38 @@ func buildMethods(typ *ast.InterfaceType) ([]mocksie.Method, error) {
 			continue
 		}
 
-		params, err := buildParams(funcType)
+		params, err := buildParams(funcType, fset)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		results, err := buildResults(funcType, fset)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		// Build the method
-		methods = append(methods, mocksie.Method{
-			Name:    field.Names[0].Name,
-			Params:  params,
-			Results: buildResults(funcType),
-		})
+		method := mocksie.Method{
+			Name:     field.Names[0].Name,
+			Params:   params,
+			Results:  results,
+			Position: buildPosition(field.Names[0].Pos(), fset),
+		}
+		_, isDoc := findDirective(field.Doc, ignoreDirective)
+		_, isComment := findDirective(field.Comment, ignoreDirective)
+		if isDoc || isComment {
+			ignored = append(ignored, method)
+			continue
+		}
+		methods = append(methods, method)
 	}
-	return methods, nil
+	return methods, ignored, nil
 }
 
 // buildResults Returns the results (return values) of an interface method.
-func buildResults(funcType *ast.FuncType) []mocksie.Result {
+func buildResults(funcType *ast.FuncType, fset *token.FileSet) ([]mocksie.Result, error) {
 	results := make([]mocksie.Result, 0)
 	if funcType.Results == nil {
-		return results // No function results
+		return results, nil // No function results
 	}
 	for i := range funcType.Results.List {
 		field := funcType.Results.List[i]
@@ -153,11 +381,14 @@ func buildResults(funcType *ast.FuncType) []mocksie.Result {
 		// Expect the field to be an identifier
 		typ, ok := field.Type.(*ast.Ident)
 		if !ok {
-			continue
+			return nil, unsupported("result type "+types.ExprString(field.Type), field.Type.Pos(), fset)
 		}
 
 		// The result may not be named
 		name := ""
+		if len(field.Names) > 1 {
+			return nil, unsupported("results sharing a type "+typ.Name, field.Pos(), fset)
+		}
 		if len(field.Names) > 0 {
 			name = field.Names[0].Name
 		}
@@ -168,15 +399,18 @@ func buildResults(funcType *ast.FuncType) []mocksie.Result {
 			Type: typ.Name,
 		})
 	}
-	return results
+	return results, nil
 }
 
 // buildParams Returns the parameters of an interface method.
-func buildParams(funcType *ast.FuncType) ([]mocksie.Param, error) {
+func buildParams(funcType *ast.FuncType, fset *token.FileSet) ([]mocksie.Param, error) {
 	params := make([]mocksie.Param, 0)
 	for _, field := range funcType.Params.List {
 		// The param may not be named
 		name := ""
+		if len(field.Names) > 1 {
+			return nil, unsupported("parameters sharing a type "+types.ExprString(field.Type), field.Pos(), fset)
+		}
 		if len(field.Names) > 0 {
 			name = field.Names[0].Name
 		}
@@ -192,7 +426,7 @@ func buildParams(funcType *ast.FuncType) ([]mocksie.Param, error) {
 		case *ast.SelectorExpr:
 			ident, ok := typ.X.(*ast.Ident)
 			if !ok {
-				return nil, fmt.Errorf("expected *ast.Ident, but got something else")
+				return nil, unsupported("parameter type "+types.ExprString(typ), typ.Pos(), fset)
 			}
 
 			// Build the param
@@ -202,9 +436,16 @@ func buildParams(funcType *ast.FuncType) ([]mocksie.Param, error) {
 			})
 
 		default:
-			// Not supported
-			continue
+			return nil, unsupported("parameter type "+types.ExprString(typ), typ.Pos(), fset)
 		}
 	}
 	return params, nil
 }
+
+// unsupported Returns the error for a construct that cannot be mocked.
+func unsupported(construct string, pos token.Pos, fset *token.FileSet) error {
+	return &mocksie.UnsupportedError{
+		Construct: construct,
+		Position:  buildPosition(pos, fset),
+	}
+}
@@ -1,10 +1,12 @@
 package parser
 
 import (
+	"errors"
 	"go/parser"
 	"go/token"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/nickwallen/mocksie/internal"
@@ -79,7 +81,7 @@ func Test_FileParser_FindInterfaces_OK(t *testing.T) {
 				// No interfaces defined here
 			`),
 			name: "greeter",
-			err:  errNotFound,
+			err:  ErrNotFound,
 		},
 		{
 			testCase: "interface-not-found",
@@ -89,7 +91,7 @@ func Test_FileParser_FindInterfaces_OK(t *testing.T) {
 				}
 			`),
 			name: "doesNotExist",
-			err:  errNotFound,
+			err:  ErrNotFound,
 		},
 		{
 			testCase: "results-named",
@@ -205,6 +207,86 @@ func Test_FileParser_FindInterfaces_OK(t *testing.T) {
 				},
 			},
 		},
+		{
+			testCase: "type-params",
+			code: []byte(`
+				package main
+				type store[K comparable, V any] interface {
+					Get(key K) (V, error)
+				}
+			`),
+			name: "store",
+			expected: &mocksie.Interface{
+				Name:    "store",
+				Package: "main",
+				Imports: []mocksie.Import{},
+				TypeParams: []mocksie.TypeParam{
+					{Name: "K", Constraint: "comparable"},
+					{Name: "V", Constraint: "any"},
+				},
+				Methods: []mocksie.Method{
+					{
+						Name: "Get",
+						Params: []mocksie.Param{
+							{Name: "key", Type: "K"},
+						},
+						Results: []mocksie.Result{
+							{Name: "", Type: "V"},
+							{Name: "", Type: "error"},
+						},
+					},
+				},
+			},
+		},
+		{
+			testCase: "ignore",
+			code: []byte(`
+				package main
+				type greeter interface {
+					SayHello(name string) (string, error)
+					//mocksie:ignore
+					SayGoodbye(name string) (string, error)
+					Close() error //mocksie:ignore
+				}
+			`),
+			name: "greeter",
+			expected: &mocksie.Interface{
+				Name:    "greeter",
+				Package: "main",
+				Imports: []mocksie.Import{},
+				Methods: []mocksie.Method{
+					{
+						Name: "SayHello",
+						Params: []mocksie.Param{
+							{Name: "name", Type: "string"},
+						},
+						Results: []mocksie.Result{
+							{Name: "", Type: "string"},
+							{Name: "", Type: "error"},
+						},
+					},
+				},
+				Ignored: []mocksie.Method{
+					{
+						Name: "SayGoodbye",
+						Params: []mocksie.Param{
+							{Name: "name", Type: "string"},
+						},
+						Results: []mocksie.Result{
+							{Name: "", Type: "string"},
+							{Name: "", Type: "error"},
+						},
+					},
+					{
+						Name:   "Close",
+						Params: []mocksie.Param{},
+						Results: []mocksie.Result{
+							{Name: "", Type: "error"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	// Create a file for the source code
@@ -226,16 +308,60 @@ func Test_FileParser_FindInterfaces_OK(t *testing.T) {
 			p, err := New(file.Name())
 			require.NoError(t, err)
 
-			// Find all interfaces
+			// Find all interfaces; positions are validated separately
 			found, err := p.FindInterface(test.name)
 			if test.expected != nil {
-				require.Equal(t, test.expected, found)
+				require.Equal(t, test.expected, withoutPositions(found))
 			}
 			require.Equal(t, test.err, err)
 		})
 	}
 }
 
+func Test_FileParser_FindInterfaces_Positions(t *testing.T) {
+	// Create a file for the source code
+	file, err := ioutil.TempFile("", "interfaces.go")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Write the source code to the file
+	code := []byte(`package main
+
+type greeter interface {
+	SayHello(name string) (string, error)
+	SayGoodbye(name string) (string, error)
+}
+`)
+	err = ioutil.WriteFile(file.Name(), code, 0700)
+	require.NoError(t, err)
+
+	// Find the interface
+	p, err := New(file.Name())
+	require.NoError(t, err)
+	found, err := p.FindInterface("greeter")
+	require.NoError(t, err)
+
+	// Validate the positions
+	require.Equal(t, mocksie.Position{Filename: file.Name(), Line: 3, Column: 6}, found.Position)
+	require.Equal(t, mocksie.Position{Filename: file.Name(), Line: 4, Column: 2}, found.Methods[0].Position)
+	require.Equal(t, mocksie.Position{Filename: file.Name(), Line: 5, Column: 2}, found.Methods[1].Position)
+}
+
+// withoutPositions returns the interface with the positions of its declarations removed.
+func withoutPositions(iface *mocksie.Interface) *mocksie.Interface {
+	if iface == nil {
+		return nil
+	}
+	iface.Position = mocksie.Position{}
+	for i := range iface.Methods {
+		iface.Methods[i].Position = mocksie.Position{}
+	}
+	for i := range iface.Ignored {
+		iface.Ignored[i].Position = mocksie.Position{}
+	}
+	return iface
+}
+
 func Test_FileParser_NewFileParser_OK(t *testing.T) {
 	// Create a file for the source code
 	file, err := ioutil.TempFile("", "interfaces.go")
@@ -262,3 +388,181 @@ func Test_FileParser_NewFileParser_FileDoesNotExist(t *testing.T) {
 	_, err := New("/this/file/does/not/exist.go")
 	require.Error(t, err)
 }
+
+func Test_FindInterfaceIn(t *testing.T) {
+	tests := []struct {
+		testCase string
+		path     string
+		name     string
+		err      error
+	}{
+		{
+			testCase: "file",
+			path:     "../testdata/greeters.go",
+			name:     "helloGreeter",
+		},
+		{
+			testCase: "package",
+			path:     "../testdata",
+			name:     "helloGreeter",
+		},
+		{
+			testCase: "not-found",
+			path:     "../testdata",
+			name:     "doesNotExist",
+			err:      ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.testCase, func(t *testing.T) {
+			found, err := FindInterfaceIn(test.path, test.name)
+			require.Equal(t, test.err, err)
+			if test.err == nil {
+				require.Equal(t, test.name, found.Name)
+				require.Equal(t, "greeters.go", filepath.Base(found.Position.Filename))
+			}
+		})
+	}
+}
+
+func Test_FileParser_FindAnnotatedInterfaces(t *testing.T) {
+	// Create a file for the source code
+	file, err := ioutil.TempFile("", "interfaces.go")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Write the source code to the file
+	code := []byte(`package main
+
+// greeter is mocked.
+//mocksie:generate
+type greeter interface {
+	SayHello(name string) (string, error)
+}
+
+// ignored is not mocked.
+type ignored interface {
+	SayHello(name string) (string, error)
+}
+
+type (
+	// helloGreeter is mocked with options.
+	//mocksie:generate out=mock_hello.go name=fakeHelloGreeter returns
+	helloGreeter interface {
+		SayHello(name string) (string, error)
+	}
+
+	goodbyeGreeter interface {
+		SayGoodbye(name string) (string, error)
+	}
+)
+`)
+	err = ioutil.WriteFile(file.Name(), code, 0700)
+	require.NoError(t, err)
+
+	// Find the annotated interfaces
+	p, err := New(file.Name())
+	require.NoError(t, err)
+	annotated, err := p.FindAnnotatedInterfaces()
+	require.NoError(t, err)
+	require.Len(t, annotated, 2)
+	require.Equal(t, "greeter", annotated[0].Name)
+	require.Equal(t, map[string]string{}, annotated[0].Options)
+	require.Equal(t, "helloGreeter", annotated[1].Name)
+	require.Equal(t, map[string]string{"out": "mock_hello.go", "name": "fakeHelloGreeter", "returns": "true"}, annotated[1].Options)
+}
+
+func Test_FileParser_FindAnnotatedInterfaces_InvalidOption(t *testing.T) {
+	// Create a file for the source code
+	file, err := ioutil.TempFile("", "interfaces.go")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// Write the source code to the file
+	code := []byte(`package main
+
+//mocksie:generate =mock.go
+type greeter interface {
+	SayHello(name string) (string, error)
+}
+`)
+	err = ioutil.WriteFile(file.Name(), code, 0700)
+	require.NoError(t, err)
+
+	p, err := New(file.Name())
+	require.NoError(t, err)
+	_, err = p.FindAnnotatedInterfaces()
+	require.EqualError(t, err, file.Name()+`:4:6: invalid option "=mock.go"; expected key=value`)
+}
+
+func Test_FileParser_FindInterfaces_All(t *testing.T) {
+	p, err := New("../testdata/greeters.go")
+	require.NoError(t, err)
+	found, err := p.FindInterfaces()
+	require.NoError(t, err)
+	names := make([]string, 0, len(found))
+	for _, iface := range found {
+		names = append(names, iface.Name)
+	}
+	require.Equal(t, []string{"helloGreeter", "goodbyeGreeter"}, names)
+}
+
+func Test_FileParser_FindInterface_Unsupported(t *testing.T) {
+	tests := []struct {
+		testCase  string
+		method    string
+		construct string
+		column    int
+	}{
+		{
+			testCase:  "embedded-interface",
+			method:    "io.Reader",
+			construct: "embedded interface io.Reader",
+			column:    2,
+		},
+		{
+			testCase:  "param-type",
+			method:    "Write(p []byte) error",
+			construct: "parameter type []byte",
+			column:    10,
+		},
+		{
+			testCase:  "params-sharing-type",
+			method:    "SayHello(first, last string) error",
+			construct: "parameters sharing a type string",
+			column:    11,
+		},
+		{
+			testCase:  "result-type",
+			method:    "Names() []string",
+			construct: "result type []string",
+			column:    10,
+		},
+	}
+
+	// Create a file for the source code
+	file, err := ioutil.TempFile("", "interfaces.go")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	for _, test := range tests {
+		t.Run(test.testCase, func(t *testing.T) {
+			code := "package main\n\nimport \"io\"\n\ntype greeter interface {\n\t" + test.method + "\n}\n"
+			err = ioutil.WriteFile(file.Name(), []byte(code), 0700)
+			require.NoError(t, err)
+
+			p, err := New(file.Name())
+			require.NoError(t, err)
+			_, err = p.FindInterface("greeter")
+			var unsupported *mocksie.UnsupportedError
+			require.True(t, errors.As(err, &unsupported))
+			require.Equal(t, test.construct, unsupported.Construct)
+			require.Equal(t, mocksie.Position{Filename: file.Name(), Line: 6, Column: test.column}, unsupported.Position)
+
+			// Interfaces that cannot be mocked are not found
+			found, err := p.FindInterfaces()
+			require.NoError(t, err)
+			require.Empty(t, found)
+		})
+	}
+}
@@ -1,3 +1,10 @@
+// Code generated by mocksie (devel). DO NOT EDIT.
+//
+// Interface: goodbyeGreeter
+// Package:   main
+// Source:    ../../internal/testdata/greeters.go
+// Command:   mocksie --in ../../internal/testdata/greeters.go --name goodbyeGreeter
+
 package main
 
 import (
@@ -9,6 +16,9 @@ type mockGoodbyeGreeter struct {
 	DoSayGoodbye func(in io.Reader, out io.Writer) error
 }
 
+// Ensure that mockGoodbyeGreeter implements the goodbyeGreeter interface.
+var _ goodbyeGreeter = (*mockGoodbyeGreeter)(nil)
+
 // SayGoodbye relies on DoSayGoodbye for defining its behavior. If this is causing a panic,
 // define DoSayGoodbye within your test case.
 func (m *mockGoodbyeGreeter) SayGoodbye(in io.Reader, out io.Writer) error {
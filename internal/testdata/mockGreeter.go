@@ -1,3 +1,10 @@
+// Code generated by mocksie (devel). DO NOT EDIT.
+//
+// Interface: greeter
+// Package:   main
+// Source:    ../../internal/testdata/greeter.go
+// Command:   mocksie --in ../../internal/testdata/greeter.go --name greeter
+
 package main
 
 import (
@@ -10,6 +17,9 @@ type mockGreeter struct {
 	DoSayGoodbye func(in io.Reader, out io.Writer) error
 }
 
+// Ensure that mockGreeter implements the greeter interface.
+var _ greeter = (*mockGreeter)(nil)
+
 // SayHello relies on DoSayHello for defining its behavior. If this is causing a panic,
 // define DoSayHello within your test case.
 func (m *mockGreeter) SayHello(in io.Reader, out io.Writer) error {
@@ -1,3 +1,10 @@
+// Code generated by mocksie (devel). DO NOT EDIT.
+//
+// Interface: helloGreeter
+// Package:   main
+// Source:    ../../internal/testdata/greeters.go
+// Command:   mocksie --in ../../internal/testdata/greeters.go --name helloGreeter
+
 package main
 
 import (
@@ -9,6 +16,9 @@ type mockHelloGreeter struct {
 	DoSayHello func(in io.Reader, out io.Writer) error
 }
 
+// Ensure that mockHelloGreeter implements the helloGreeter interface.
+var _ helloGreeter = (*mockHelloGreeter)(nil)
+
 // SayHello relies on DoSayHello for defining its behavior. If this is causing a panic,
 // define DoSayHello within your test case.
 func (m *mockHelloGreeter) SayHello(in io.Reader, out io.Writer) error {
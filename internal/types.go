@@ -1,38 +1,56 @@
 package mocksie
 
-// Interface is an interface that will need to be mocked.
+// Interface is an interface that will need to be mocked. The methods annotated
+// with a //mocksie:ignore comment are Ignored rather than mocked.
 type Interface struct {
-	Name    string
-	Package Package
-	Imports []Import
-	Methods []Method
+	Name       string      `json:"name"`
+	Package    Package     `json:"package"`
+	Imports    []Import    `json:"imports"`
+	TypeParams []TypeParam `json:"typeParams,omitempty"`
+	Methods    []Method    `json:"methods"`
+	Ignored    []Method    `json:"ignored,omitempty"`
+	Position   Position    `json:"position"`
+}
+
+// Position is the position of a declaration within a source file.
+type Position struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
 }
 
 // Import is an imported package.
 type Import struct {
-	Path string
+	Path string `json:"path"`
 }
 
 // Package is the package in which an Interface is defined.
 type Package string
 
+// TypeParam is a type parameter of a generic Interface.
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+}
+
 // Method is a method that is part of an Interface. There are one or more methods
 // within an Interface.
 type Method struct {
-	Name    string
-	Params  []Param
-	Results []Result
+	Name     string   `json:"name"`
+	Params   []Param  `json:"params"`
+	Results  []Result `json:"results"`
+	Position Position `json:"position"`
 }
 
 // Param is a parameter to a Method call. A Method has zero or more call parameters.
 type Param struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // Result is the result that is returned by a Method call. A Method has zero or
 // more results that can be either named or unnamed.
 type Result struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
@@ -0,0 +1,96 @@
+// Package mocksie generates mocks for Go interfaces that don't require any additional
+// mocking or test packages. It allows other tools to load interfaces and generate
+// their mocks without relying on the mocksie command.
+//
+//	iface, err := mocksie.Load("greeter.go", "greeter")
+//	if err != nil {
+//		return err
+//	}
+//	return mocksie.Generate(out, iface, mocksie.Options{Returns: true})
+package mocksie
+
+import (
+	"io"
+
+	ir "github.com/nickwallen/mocksie/internal"
+	"github.com/nickwallen/mocksie/internal/generator"
+	"github.com/nickwallen/mocksie/internal/parser"
+)
+
+// DefaultMockName is the template that defines the name of a mock by default.
+const DefaultMockName = generator.DefaultMockName
+
+// IRVersion is the version of the JSON schema of the intermediate representation.
+const IRVersion = ir.IRVersion
+
+// ErrInterfaceNotFound is the error returned when the interface is not found.
+var ErrInterfaceNotFound = parser.ErrNotFound
+
+type (
+	// Interface is the intermediate representation of an interface that is mocked.
+	Interface = ir.Interface
+
+	// Method is a method of an Interface.
+	Method = ir.Method
+
+	// Param is a parameter of a Method.
+	Param = ir.Param
+
+	// Result is a result of a Method.
+	Result = ir.Result
+
+	// TypeParam is a type parameter of a generic Interface.
+	TypeParam = ir.TypeParam
+
+	// Import is a package imported by the file defining an Interface.
+	Import = ir.Import
+
+	// Package is the package in which an Interface is defined.
+	Package = ir.Package
+
+	// Position is the position of a declaration within a source file.
+	Position = ir.Position
+
+	// UnsupportedError is the error returned when an interface uses a construct
+	// that cannot be mocked.
+	UnsupportedError = ir.UnsupportedError
+
+	// Options defines the optional features that are generated for a mock.
+	Options = generator.Options
+
+	// Header is the provenance recorded in the generated code header of a mock.
+	Header = generator.Header
+)
+
+// Load loads the interface with the given name from a path, which is either a
+// file or a directory containing a package. It returns ErrInterfaceNotFound if
+// the interface is not found and an UnsupportedError if it cannot be mocked.
+func Load(path, name string) (*Interface, error) {
+	return parser.FindInterfaceIn(path, name)
+}
+
+// LoadAll loads the interfaces from a path, which is either a file or a directory
+// containing a package, except for those that cannot be mocked.
+func LoadAll(path string) ([]*Interface, error) {
+	return parser.FindInterfacesIn(path)
+}
+
+// Generate generates a mock for an Interface and writes it to w.
+func Generate(w io.Writer, iface *Interface, opts Options) error {
+	gen, err := generator.New(w, opts)
+	if err != nil {
+		return err
+	}
+	return gen.GenerateMock(iface)
+}
+
+// EncodeIR writes the intermediate representation of an Interface as JSON.
+func EncodeIR(w io.Writer, iface *Interface) error {
+	return ir.EncodeIR(w, iface)
+}
+
+// DecodeIR reads the intermediate representation of an Interface from JSON. It
+// returns an error if the version of the representation is not IRVersion.
+func DecodeIR(r io.Reader) (*Interface, error) {
+	return ir.DecodeIR(r)
+}
@@ -0,0 +1,73 @@
+package mocksie
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generate_OK(t *testing.T) {
+	// Read the expected mock output
+	expectedMock, err := ioutil.ReadFile("internal/testdata/mockGreeter.go")
+	require.NoError(t, err)
+
+	iface, err := Load("internal/testdata/greeter.go", "greeter")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = Generate(&out, iface, Options{
+		Header: Header{
+			Version: "(devel)",
+			Source:  "../../internal/testdata/greeter.go",
+			Command: "mocksie --in ../../internal/testdata/greeter.go --name greeter",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(expectedMock), out.String())
+}
+
+func Test_Load_Package(t *testing.T) {
+	iface, err := Load("internal/testdata", "helloGreeter")
+	require.NoError(t, err)
+	require.Equal(t, "helloGreeter", iface.Name)
+	require.Len(t, iface.Methods, 1)
+
+	ifaces, err := LoadAll("internal/testdata/greeters.go")
+	require.NoError(t, err)
+	require.Len(t, ifaces, 2)
+}
+
+func Test_Load_InterfaceNotFound(t *testing.T) {
+	_, err := Load("internal/testdata/greeter.go", "doesNotExist")
+	require.True(t, errors.Is(err, ErrInterfaceNotFound))
+}
+
+func Test_Load_Unsupported(t *testing.T) {
+	file, err := ioutil.TempFile("", "reader.go")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("package main\n\nimport \"io\"\n\ntype readCloser interface {\n\tio.Reader\n\tClose() error\n}\n")
+	require.NoError(t, err)
+
+	_, err = Load(file.Name(), "readCloser")
+	var unsupported *UnsupportedError
+	require.True(t, errors.As(err, &unsupported))
+	require.Equal(t, "embedded interface io.Reader", unsupported.Construct)
+	require.Equal(t, Position{Filename: file.Name(), Line: 6, Column: 2}, unsupported.Position)
+}
+
+func Test_IR_RoundTrip(t *testing.T) {
+	iface, err := Load("internal/testdata/greeter.go", "greeter")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = EncodeIR(&out, iface)
+	require.NoError(t, err)
+	decoded, err := DecodeIR(&out)
+	require.NoError(t, err)
+	require.Equal(t, iface, decoded)
+}